@@ -1,17 +1,16 @@
+//go:build amd64 && !generic
 // +build amd64,!generic
 
-// Code generated by command: go run main.go -output ./generated -bit 256 -opt A -modulus 0x30644e72e131a029b85045b68181585d97816a916871ca8d3c208c16d87cfd47 -arch ADX. DO NOT EDIT.
+// Code generated by fieldgen -modulus 0x30644e72e131a029b85045b68181585d97816a916871ca8d3c208c16d87cfd47 -type fe -output arithmetic -wide wfe. DO NOT EDIT.
 
 #include "textflag.h"
 
 // func add(c *[4]uint64, a *[4]uint64, b *[4]uint64)
 TEXT ·add(SB), NOSPLIT, $0-24
-	// |
 	MOVQ a+8(FP), DI
 	MOVQ b+16(FP), SI
 	XORQ AX, AX
 
-	// |
 	MOVQ (DI), CX
 	ADDQ (SI), CX
 	MOVQ 8(DI), DX
@@ -22,7 +21,6 @@ TEXT ·add(SB), NOSPLIT, $0-24
 	ADCQ 24(SI), R9
 	ADCQ $0x00, AX
 
-	// |
 	MOVQ CX, R10
 	SUBQ ·modulus+0(SB), R10
 	MOVQ DX, R11
@@ -33,7 +31,6 @@ TEXT ·add(SB), NOSPLIT, $0-24
 	SBBQ ·modulus+24(SB), R13
 	SBBQ $0x00, AX
 
-	// |
 	MOVQ    c+0(FP), DI
 	CMOVQCC R10, CX
 	MOVQ    CX, (DI)
@@ -45,19 +42,12 @@ TEXT ·add(SB), NOSPLIT, $0-24
 	MOVQ    R9, 24(DI)
 	RET
 
-	// | 
-
-/* end                                     */
-
-
-// func add(a *[4]uint64, b *[4]uint64)
+// func addAssign(a *[4]uint64, b *[4]uint64)
 TEXT ·addAssign(SB), NOSPLIT, $0-16
-	// |
 	MOVQ a+0(FP), DI
 	MOVQ b+8(FP), SI
 	XORQ AX, AX
 
-	// |
 	MOVQ (DI), CX
 	ADDQ (SI), CX
 	MOVQ 8(DI), DX
@@ -68,7 +58,6 @@ TEXT ·addAssign(SB), NOSPLIT, $0-16
 	ADCQ 24(SI), R9
 	ADCQ $0x00, AX
 
-	// |
 	MOVQ CX, R10
 	SUBQ ·modulus+0(SB), R10
 	MOVQ DX, R11
@@ -79,7 +68,6 @@ TEXT ·addAssign(SB), NOSPLIT, $0-16
 	SBBQ ·modulus+24(SB), R13
 	SBBQ $0x00, AX
 
-	// |
 	CMOVQCC R10, CX
 	MOVQ    CX, (DI)
 	CMOVQCC R11, DX
@@ -90,18 +78,11 @@ TEXT ·addAssign(SB), NOSPLIT, $0-16
 	MOVQ    R9, 24(DI)
 	RET
 
-	// | 
-
-/* end                                     */
-
-
 // func laddAssign(a *[4]uint64, b *[4]uint64)
 TEXT ·laddAssign(SB), NOSPLIT, $0-16
-	// |
 	MOVQ a+0(FP), DI
 	MOVQ b+8(FP), SI
 
-	// |
 	MOVQ (DI), CX
 	ADDQ (SI), CX
 	MOVQ 8(DI), DX
@@ -110,23 +91,15 @@ TEXT ·laddAssign(SB), NOSPLIT, $0-16
 	ADCQ 16(SI), R8
 	MOVQ 24(DI), R9
 	ADCQ 24(SI), R9
-	ADCQ $0x00, AX
 
-	// |
 	MOVQ CX, (DI)
 	MOVQ DX, 8(DI)
 	MOVQ R8, 16(DI)
 	MOVQ R9, 24(DI)
 	RET
 
-	// | 
-
-/* end                                     */
-
-
 // func double(c *[4]uint64, a *[4]uint64)
 TEXT ·double(SB), NOSPLIT, $0-16
-	// |
 	MOVQ a+8(FP), DI
 	XORQ AX, AX
 	MOVQ (DI), CX
@@ -139,7 +112,6 @@ TEXT ·double(SB), NOSPLIT, $0-16
 	ADCQ R8, R8
 	ADCQ $0x00, AX
 
-	// |
 	MOVQ CX, R9
 	SUBQ ·modulus+0(SB), R9
 	MOVQ DX, R10
@@ -150,7 +122,6 @@ TEXT ·double(SB), NOSPLIT, $0-16
 	SBBQ ·modulus+24(SB), R12
 	SBBQ $0x00, AX
 
-	// |
 	MOVQ    c+0(FP), DI
 	CMOVQCC R9, CX
 	MOVQ    CX, (DI)
@@ -162,14 +133,8 @@ TEXT ·double(SB), NOSPLIT, $0-16
 	MOVQ    R8, 24(DI)
 	RET
 
-	// | 
-
-/* end                                     */
-
-
 // func doubleAssign(a *[4]uint64)
 TEXT ·doubleAssign(SB), NOSPLIT, $0-8
-	// |
 	MOVQ a+0(FP), DI
 	XORQ AX, AX
 	MOVQ (DI), CX
@@ -182,7 +147,6 @@ TEXT ·doubleAssign(SB), NOSPLIT, $0-8
 	ADCQ R8, R8
 	ADCQ $0x00, AX
 
-	// |
 	MOVQ CX, R9
 	SUBQ ·modulus+0(SB), R9
 	MOVQ DX, R10
@@ -193,7 +157,6 @@ TEXT ·doubleAssign(SB), NOSPLIT, $0-8
 	SBBQ ·modulus+24(SB), R12
 	SBBQ $0x00, AX
 
-	// |
 	CMOVQCC R9, CX
 	MOVQ    CX, (DI)
 	CMOVQCC R10, DX
@@ -204,14 +167,8 @@ TEXT ·doubleAssign(SB), NOSPLIT, $0-8
 	MOVQ    R8, 24(DI)
 	RET
 
-	// | 
-
-/* end                                     */
-
-
 // func sub(c *[4]uint64, a *[4]uint64, b *[4]uint64)
 TEXT ·sub(SB), NOSPLIT, $0-24
-	// |
 	MOVQ a+8(FP), DI
 	MOVQ b+16(FP), SI
 	XORQ AX, AX
@@ -224,7 +181,6 @@ TEXT ·sub(SB), NOSPLIT, $0-24
 	MOVQ 24(DI), R9
 	SBBQ 24(SI), R9
 
-	// |
 	MOVQ    ·modulus+0(SB), SI
 	CMOVQCC AX, SI
 	MOVQ    ·modulus+8(SB), R10
@@ -234,7 +190,6 @@ TEXT ·sub(SB), NOSPLIT, $0-24
 	MOVQ    ·modulus+24(SB), R12
 	CMOVQCC AX, R12
 
-	// |
 	MOVQ c+0(FP), DI
 	ADDQ SI, CX
 	MOVQ CX, (DI)
@@ -246,14 +201,8 @@ TEXT ·sub(SB), NOSPLIT, $0-24
 	MOVQ R9, 24(DI)
 	RET
 
-	// | 
-
-/* end                                     */
-
-
 // func subAssign(a *[4]uint64, b *[4]uint64)
 TEXT ·subAssign(SB), NOSPLIT, $0-16
-	// |
 	MOVQ a+0(FP), DI
 	MOVQ b+8(FP), SI
 	XORQ AX, AX
@@ -266,7 +215,6 @@ TEXT ·subAssign(SB), NOSPLIT, $0-16
 	MOVQ 24(DI), R9
 	SBBQ 24(SI), R9
 
-	// |
 	MOVQ    ·modulus+0(SB), SI
 	CMOVQCC AX, SI
 	MOVQ    ·modulus+8(SB), R10
@@ -276,7 +224,6 @@ TEXT ·subAssign(SB), NOSPLIT, $0-16
 	MOVQ    ·modulus+24(SB), R12
 	CMOVQCC AX, R12
 
-	// |
 	ADDQ SI, CX
 	MOVQ CX, (DI)
 	ADCQ R10, DX
@@ -287,19 +234,12 @@ TEXT ·subAssign(SB), NOSPLIT, $0-16
 	MOVQ R9, 24(DI)
 	RET
 
-	// | 
-
-/* end                                     */
-
-
-// func lsubAssign(a *[4]uint64, b *[4]uint64)
+// func lsubAssign(a *[4]uint64, b *[4]uint64) uint64
 TEXT ·lsubAssign(SB), NOSPLIT, $0-24
-	// |
 	MOVQ a+0(FP), DI
 	MOVQ b+8(FP), SI
 	XORQ AX, AX
 
-	// |
 	MOVQ (DI), CX
 	SUBQ (SI), CX
 	MOVQ 8(DI), DX
@@ -310,7 +250,6 @@ TEXT ·lsubAssign(SB), NOSPLIT, $0-24
 	SBBQ 24(SI), R9
 	ADCQ $0x00, AX
 
-	// |
 	MOVQ CX, (DI)
 	MOVQ DX, 8(DI)
 	MOVQ R8, 16(DI)
@@ -318,17 +257,11 @@ TEXT ·lsubAssign(SB), NOSPLIT, $0-24
 	MOVQ AX, ret+16(FP)
 	RET
 
-	// | 
-
-/* end                                     */
-
-
-// func _neg(c *[4]uint64, a *[4]uint64, p *[4]uint64)
-TEXT ·_neg(SB), NOSPLIT, $0-16
-	// |
+// func neg(c *[4]uint64, a *[4]uint64)
+TEXT ·neg(SB), NOSPLIT, $0-16
 	MOVQ a+8(FP), DI
+	XORQ R9, R9
 
-	// |
 	MOVQ ·modulus+0(SB), CX
 	SUBQ (DI), CX
 	MOVQ ·modulus+8(SB), DX
@@ -338,7 +271,15 @@ TEXT ·_neg(SB), NOSPLIT, $0-16
 	MOVQ ·modulus+24(SB), R8
 	SBBQ 24(DI), R8
 
-	// |
+	MOVQ    (DI), AX
+	ORQ     8(DI), AX
+	ORQ     16(DI), AX
+	ORQ     24(DI), AX
+	CMOVQEQ R9, CX
+	CMOVQEQ R9, DX
+	CMOVQEQ R9, SI
+	CMOVQEQ R9, R8
+
 	MOVQ c+0(FP), DI
 	MOVQ CX, (DI)
 	MOVQ DX, 8(DI)
@@ -346,196 +287,249 @@ TEXT ·_neg(SB), NOSPLIT, $0-16
 	MOVQ R8, 24(DI)
 	RET
 
-	// | 
-
-/* end                                     */
-
-
 // func mulADX(c *[4]uint64, a *[4]uint64, b *[4]uint64)
 TEXT ·mulADX(SB), NOSPLIT, $0-24
-	// | 
-
-/* inputs                                  */
-
 	MOVQ a+8(FP), DI
 	MOVQ b+16(FP), SI
 	XORQ AX, AX
 
-	// | 
-
-/* i = 0                                   */
-
 	// | a0 @ DX
-	MOVQ (DI), DX
-
-	// | a0 * b0 
+	MOVQ  (DI), DX
 	MULXQ (SI), CX, R8
-
-	// | a0 * b1 
 	MULXQ 8(SI), AX, R9
 	ADCXQ AX, R8
-
-	// | a0 * b2 
 	MULXQ 16(SI), AX, R10
 	ADCXQ AX, R9
-
-	// | a0 * b3 
 	MULXQ 24(SI), AX, R11
 	ADCXQ AX, R10
 	ADCQ  $0x00, R11
 
-	// | 
-
-/* i = 1                                   */
-
 	// | a1 @ DX
-	MOVQ 8(DI), DX
-	XORQ R12, R12
-
-	// | a1 * b0 
+	MOVQ  8(DI), DX
+	XORQ  R12, R12
 	MULXQ (SI), AX, BX
 	ADOXQ AX, R8
 	ADCXQ BX, R9
-
-	// | a1 * b1 
 	MULXQ 8(SI), AX, BX
 	ADOXQ AX, R9
 	ADCXQ BX, R10
-
-	// | a1 * b2 
 	MULXQ 16(SI), AX, BX
 	ADOXQ AX, R10
 	ADCXQ BX, R11
-
-	// | a1 * b3 
 	MULXQ 24(SI), AX, BX
 	ADOXQ AX, R11
 	ADOXQ R12, R12
 	ADCXQ BX, R12
 
-	// | 
-
-/* i = 2                                   */
-
 	// | a2 @ DX
-	MOVQ 16(DI), DX
-	XORQ R13, R13
-
-	// | a2 * b0 
+	MOVQ  16(DI), DX
+	XORQ  R13, R13
 	MULXQ (SI), AX, BX
 	ADOXQ AX, R9
 	ADCXQ BX, R10
-
-	// | a2 * b1 
 	MULXQ 8(SI), AX, BX
 	ADOXQ AX, R10
 	ADCXQ BX, R11
-
-	// | a2 * b2 
 	MULXQ 16(SI), AX, BX
 	ADOXQ AX, R11
 	ADCXQ BX, R12
-
-	// | a2 * b3 
 	MULXQ 24(SI), AX, BX
 	ADOXQ AX, R12
 	ADOXQ R13, R13
 	ADCXQ BX, R13
 
-	// | 
-
-/* i = 3                                   */
-
 	// | a3 @ DX
-	MOVQ 24(DI), DX
-	XORQ DI, DI
-
-	// | a3 * b0 
+	MOVQ  24(DI), DX
+	XORQ  DI, DI
 	MULXQ (SI), AX, BX
 	ADOXQ AX, R10
 	ADCXQ BX, R11
-
-	// | a3 * b1 
 	MULXQ 8(SI), AX, BX
 	ADOXQ AX, R11
 	ADCXQ BX, R12
-
-	// | a3 * b2 
 	MULXQ 16(SI), AX, BX
 	ADOXQ AX, R12
 	ADCXQ BX, R13
-
-	// | a3 * b3 
 	MULXQ 24(SI), AX, BX
 	ADOXQ AX, R13
-	ADOXQ BX, DI
-	ADCQ  $0x00, DI
-
-	// | 
-
-/* 			                                     */
-
-	// | 
-	// | W
-	// | 0   CX        | 1   R8        | 2   R9        | 3   R10       
-	// | 4   R11       | 5   R12       | 6   R13       | 7   DI        
-
-
-	// | 
-	// | W ready to mont
-	// | 0   CX        | 1   R8        | 2   R9        | 3   R10       
-	// | 4   R11       | 5   R12       | 6   R13       | 7   DI        
+	ADOXQ DI, DI
+	ADCXQ BX, DI
 
+	// | montgomery reduction
+	// | u0 = w0 * inp
+	XORQ  AX, AX
+	MOVQ  CX, DX
+	MULXQ ·inp+0(SB), DX, BX
+	MULXQ ·modulus+0(SB), AX, BX
+	ADOXQ AX, CX
+	ADCXQ BX, R8
+	MULXQ ·modulus+8(SB), AX, BX
+	ADOXQ AX, R8
+	ADCXQ BX, R9
+	MULXQ ·modulus+16(SB), AX, BX
+	ADOXQ AX, R9
+	ADCXQ BX, R10
+	MULXQ ·modulus+24(SB), AX, BX
+	ADOXQ AX, R10
+	ADCXQ BX, R11
+	ADOXQ CX, R11
+	ADCXQ CX, CX
+	MOVQ  $0x00, AX
+	ADOXQ AX, CX
 
-	// | 
+	// | u1 = w1 * inp
+	XORQ  AX, AX
+	MOVQ  R8, DX
+	MULXQ ·inp+0(SB), DX, BX
+	MULXQ ·modulus+0(SB), AX, BX
+	ADOXQ AX, R8
+	ADCXQ BX, R9
+	MULXQ ·modulus+8(SB), AX, BX
+	ADOXQ AX, R9
+	ADCXQ BX, R10
+	MULXQ ·modulus+16(SB), AX, BX
+	ADOXQ AX, R10
+	ADCXQ BX, R11
+	MULXQ ·modulus+24(SB), AX, BX
+	ADOXQ AX, R11
+	ADCXQ BX, R12
+	ADOXQ CX, R12
+	ADCXQ R8, R8
+	MOVQ  $0x00, AX
+	ADOXQ AX, R8
 
-/* montgomery reduction                    */
+	// | u2 = w2 * inp
+	XORQ  AX, AX
+	MOVQ  R9, DX
+	MULXQ ·inp+0(SB), DX, BX
+	MULXQ ·modulus+0(SB), AX, BX
+	ADOXQ AX, R9
+	ADCXQ BX, R10
+	MULXQ ·modulus+8(SB), AX, BX
+	ADOXQ AX, R10
+	ADCXQ BX, R11
+	MULXQ ·modulus+16(SB), AX, BX
+	ADOXQ AX, R11
+	ADCXQ BX, R12
+	MULXQ ·modulus+24(SB), AX, BX
+	ADOXQ AX, R12
+	ADCXQ BX, R13
+	ADOXQ R8, R13
+	ADCXQ R9, R9
+	MOVQ  $0x00, AX
+	ADOXQ AX, R9
 
-	// | clear flags
-	XORQ AX, AX
+	// | u3 = w3 * inp
+	XORQ  AX, AX
+	MOVQ  R10, DX
+	MULXQ ·inp+0(SB), DX, BX
+	MULXQ ·modulus+0(SB), AX, BX
+	ADOXQ AX, R10
+	ADCXQ BX, R11
+	MULXQ ·modulus+8(SB), AX, BX
+	ADOXQ AX, R11
+	ADCXQ BX, R12
+	MULXQ ·modulus+16(SB), AX, BX
+	ADOXQ AX, R12
+	ADCXQ BX, R13
+	MULXQ ·modulus+24(SB), AX, BX
+	ADOXQ AX, R13
+	ADCXQ BX, DI
+	ADOXQ R9, DI
+	ADCXQ R10, R10
+	MOVQ  $0x00, AX
+	ADOXQ AX, R10
 
-	// | 
+	// | modular reduction
+	MOVQ R11, CX
+	SUBQ ·modulus+0(SB), CX
+	MOVQ R12, AX
+	SBBQ ·modulus+8(SB), AX
+	MOVQ R13, BX
+	SBBQ ·modulus+16(SB), BX
+	MOVQ DI, SI
+	SBBQ ·modulus+24(SB), SI
+	SBBQ $0x00, R10
 
-/* i = 0                                   */
+	MOVQ    c+0(FP), R10
+	CMOVQCC CX, R11
+	MOVQ    R11, (R10)
+	CMOVQCC AX, R12
+	MOVQ    R12, 8(R10)
+	CMOVQCC BX, R13
+	MOVQ    R13, 16(R10)
+	CMOVQCC SI, DI
+	MOVQ    DI, 24(R10)
+	RET
 
-	// | 
-	// | W
-	// | 0   CX        | 1   R8        | 2   R9        | 3   R10       
-	// | 4   R11       | 5   R12       | 6   R13       | 7   DI        
+// func squareADX(c *[4]uint64, a *[4]uint64)
+TEXT ·squareADX(SB), NOSPLIT, $0-16
+	MOVQ a+8(FP), SI
 
+	// | cross products
+	MOVQ  (SI), DX
+	MULXQ 8(SI), R8, R9
+	MULXQ 16(SI), AX, R10
+	ADDQ  AX, R9
+	MULXQ 24(SI), AX, R11
+	ADCQ  AX, R10
+	ADCQ  $0x00, R11
+	MOVQ  8(SI), DX
+	XORQ  R12, R12
+	MULXQ 16(SI), AX, BX
+	ADOXQ AX, R10
+	ADCXQ BX, R11
+	MULXQ 24(SI), AX, BX
+	ADOXQ AX, R11
+	ADCXQ BX, R12
+	MOVQ  $0x00, AX
+	ADOXQ AX, R12
+	MOVQ  16(SI), DX
+	MULXQ 24(SI), AX, R13
+	ADDQ  AX, R12
+	ADCQ  $0x00, R13
 
-	// | | u0 = w0 * inp
+	// | double cross products
+	XORQ DI, DI
+	ADDQ R8, R8
+	ADCQ R9, R9
+	ADCQ R10, R10
+	ADCQ R11, R11
+	ADCQ R12, R12
+	ADCQ R13, R13
+	ADCQ $0x00, DI
+
+	// | diagonal
+	MOVQ  (SI), DX
+	MULXQ DX, CX, AX
+	ADDQ  AX, R8
+	MOVQ  8(SI), DX
+	MULXQ DX, AX, BX
+	ADCQ  AX, R9
+	ADCQ  BX, R10
+	MOVQ  16(SI), DX
+	MULXQ DX, AX, BX
+	ADCQ  AX, R11
+	ADCQ  BX, R12
+	MOVQ  24(SI), DX
+	MULXQ DX, AX, BX
+	ADCQ  AX, R13
+	ADCQ  BX, DI
+
+	// | montgomery reduction
+	// | u0 = w0 * inp
+	XORQ  AX, AX
 	MOVQ  CX, DX
 	MULXQ ·inp+0(SB), DX, BX
-
-	// | 
-
-/*                                         */
-
-	// | j0
-
-	// | w0 @ CX
 	MULXQ ·modulus+0(SB), AX, BX
 	ADOXQ AX, CX
 	ADCXQ BX, R8
-
-	// | j1
-
-	// | w1 @ R8
 	MULXQ ·modulus+8(SB), AX, BX
 	ADOXQ AX, R8
 	ADCXQ BX, R9
-
-	// | j2
-
-	// | w2 @ R9
 	MULXQ ·modulus+16(SB), AX, BX
 	ADOXQ AX, R9
 	ADCXQ BX, R10
-
-	// | j3
-
-	// | w3 @ R10
 	MULXQ ·modulus+24(SB), AX, BX
 	ADOXQ AX, R10
 	ADCXQ BX, R11
@@ -544,51 +538,19 @@ TEXT ·mulADX(SB), NOSPLIT, $0-24
 	MOVQ  $0x00, AX
 	ADOXQ AX, CX
 
-	// | clear flags
-	XORQ AX, AX
-
-	// | 
-
-/* i = 1                                   */
-
-	// | 
-	// | W
-	// | 0   -         | 1   R8        | 2   R9        | 3   R10       
-	// | 4   R11       | 5   R12       | 6   R13       | 7   DI        
-
-
-	// | | u1 = w1 * inp
+	// | u1 = w1 * inp
+	XORQ  AX, AX
 	MOVQ  R8, DX
 	MULXQ ·inp+0(SB), DX, BX
-
-	// | 
-
-/*                                         */
-
-	// | j0
-
-	// | w1 @ R8
 	MULXQ ·modulus+0(SB), AX, BX
 	ADOXQ AX, R8
 	ADCXQ BX, R9
-
-	// | j1
-
-	// | w2 @ R9
 	MULXQ ·modulus+8(SB), AX, BX
 	ADOXQ AX, R9
 	ADCXQ BX, R10
-
-	// | j2
-
-	// | w3 @ R10
 	MULXQ ·modulus+16(SB), AX, BX
 	ADOXQ AX, R10
 	ADCXQ BX, R11
-
-	// | j3
-
-	// | w4 @ R11
 	MULXQ ·modulus+24(SB), AX, BX
 	ADOXQ AX, R11
 	ADCXQ BX, R12
@@ -597,51 +559,19 @@ TEXT ·mulADX(SB), NOSPLIT, $0-24
 	MOVQ  $0x00, AX
 	ADOXQ AX, R8
 
-	// | clear flags
-	XORQ AX, AX
-
-	// | 
-
-/* i = 2                                   */
-
-	// | 
-	// | W
-	// | 0   -         | 1   -         | 2   R9        | 3   R10       
-	// | 4   R11       | 5   R12       | 6   R13       | 7   DI        
-
-
-	// | | u2 = w2 * inp
+	// | u2 = w2 * inp
+	XORQ  AX, AX
 	MOVQ  R9, DX
 	MULXQ ·inp+0(SB), DX, BX
-
-	// | 
-
-/*                                         */
-
-	// | j0
-
-	// | w2 @ R9
 	MULXQ ·modulus+0(SB), AX, BX
 	ADOXQ AX, R9
 	ADCXQ BX, R10
-
-	// | j1
-
-	// | w3 @ R10
 	MULXQ ·modulus+8(SB), AX, BX
 	ADOXQ AX, R10
 	ADCXQ BX, R11
-
-	// | j2
-
-	// | w4 @ R11
 	MULXQ ·modulus+16(SB), AX, BX
 	ADOXQ AX, R11
 	ADCXQ BX, R12
-
-	// | j3
-
-	// | w5 @ R12
 	MULXQ ·modulus+24(SB), AX, BX
 	ADOXQ AX, R12
 	ADCXQ BX, R13
@@ -650,51 +580,19 @@ TEXT ·mulADX(SB), NOSPLIT, $0-24
 	MOVQ  $0x00, AX
 	ADOXQ AX, R9
 
-	// | clear flags
-	XORQ AX, AX
-
-	// | 
-
-/* i = 3                                   */
-
-	// | 
-	// | W
-	// | 0   -         | 1   -         | 2   -         | 3   R10       
-	// | 4   R11       | 5   R12       | 6   R13       | 7   DI        
-
-
-	// | | u3 = w3 * inp
+	// | u3 = w3 * inp
+	XORQ  AX, AX
 	MOVQ  R10, DX
 	MULXQ ·inp+0(SB), DX, BX
-
-	// | 
-
-/*                                         */
-
-	// | j0
-
-	// | w3 @ R10
 	MULXQ ·modulus+0(SB), AX, BX
 	ADOXQ AX, R10
 	ADCXQ BX, R11
-
-	// | j1
-
-	// | w4 @ R11
 	MULXQ ·modulus+8(SB), AX, BX
 	ADOXQ AX, R11
 	ADCXQ BX, R12
-
-	// | j2
-
-	// | w5 @ R12
 	MULXQ ·modulus+16(SB), AX, BX
 	ADOXQ AX, R12
 	ADCXQ BX, R13
-
-	// | j3
-
-	// | w6 @ R13
 	MULXQ ·modulus+24(SB), AX, BX
 	ADOXQ AX, R13
 	ADCXQ BX, DI
@@ -703,16 +601,7 @@ TEXT ·mulADX(SB), NOSPLIT, $0-24
 	MOVQ  $0x00, AX
 	ADOXQ AX, R10
 
-	// | 
-	// | W montgomery reduction ends
-	// | 0   -         | 1   -         | 2   -         | 3   -         
-	// | 4   R11       | 5   R12       | 6   R13       | 7   DI        
-
-
-	// | 
-
-/* modular reduction                       */
-
+	// | modular reduction
 	MOVQ R11, CX
 	SUBQ ·modulus+0(SB), CX
 	MOVQ R12, AX
@@ -723,10 +612,6 @@ TEXT ·mulADX(SB), NOSPLIT, $0-24
 	SBBQ ·modulus+24(SB), SI
 	SBBQ $0x00, R10
 
-	// | 
-
-/* out                                     */
-
 	MOVQ    c+0(FP), R10
 	CMOVQCC CX, R11
 	MOVQ    R11, (R10)
@@ -738,16 +623,8 @@ TEXT ·mulADX(SB), NOSPLIT, $0-24
 	MOVQ    DI, 24(R10)
 	RET
 
-	// | 
-
-/* end                                     */
-
-// func mul(c *[4]uint64, a *[4]uint64, b *[4]uint64)
+// func mulNoADX(c *[4]uint64, a *[4]uint64, b *[4]uint64)
 TEXT ·mulNoADX(SB), NOSPLIT, $0-24
-	// | 
-
-/* inputs                                  */
-
 	MOVQ a+8(FP), DI
 	MOVQ b+16(FP), SI
 	MOVQ $0x00, R10
@@ -756,46 +633,38 @@ TEXT ·mulNoADX(SB), NOSPLIT, $0-24
 	MOVQ $0x00, R13
 	MOVQ $0x00, R14
 
-	// | 
-
-/* i = 0                                   */
-
 	// | a0 @ CX
 	MOVQ (DI), CX
 
-	// | a0 * b0 
+	// | a0 * b0
 	MOVQ (SI), AX
 	MULQ CX
 	MOVQ AX, R8
 	MOVQ DX, R9
 
-	// | a0 * b1 
+	// | a0 * b1
 	MOVQ 8(SI), AX
 	MULQ CX
 	ADDQ AX, R9
 	ADCQ DX, R10
 
-	// | a0 * b2 
+	// | a0 * b2
 	MOVQ 16(SI), AX
 	MULQ CX
 	ADDQ AX, R10
 	ADCQ DX, R11
 
-	// | a0 * b3 
+	// | a0 * b3
 	MOVQ 24(SI), AX
 	MULQ CX
 	ADDQ AX, R11
 	ADCQ DX, R12
 
-	// | 
-
-/* i = 1                                   */
-
 	// | a1 @ CX
 	MOVQ 8(DI), CX
 	MOVQ $0x00, BX
 
-	// | a1 * b0 
+	// | a1 * b0
 	MOVQ (SI), AX
 	MULQ CX
 	ADDQ AX, R9
@@ -803,7 +672,7 @@ TEXT ·mulNoADX(SB), NOSPLIT, $0-24
 	ADCQ $0x00, R11
 	ADCQ $0x00, BX
 
-	// | a1 * b1 
+	// | a1 * b1
 	MOVQ 8(SI), AX
 	MULQ CX
 	ADDQ AX, R10
@@ -812,28 +681,24 @@ TEXT ·mulNoADX(SB), NOSPLIT, $0-24
 	MOVQ $0x00, BX
 	ADCQ $0x00, BX
 
-	// | a1 * b2 
+	// | a1 * b2
 	MOVQ 16(SI), AX
 	MULQ CX
 	ADDQ AX, R11
 	ADCQ DX, R12
 	ADCQ BX, R13
 
-	// | a1 * b3 
+	// | a1 * b3
 	MOVQ 24(SI), AX
 	MULQ CX
 	ADDQ AX, R12
 	ADCQ DX, R13
 
-	// | 
-
-/* i = 2                                   */
-
 	// | a2 @ CX
 	MOVQ 16(DI), CX
 	MOVQ $0x00, BX
 
-	// | a2 * b0 
+	// | a2 * b0
 	MOVQ (SI), AX
 	MULQ CX
 	ADDQ AX, R10
@@ -841,7 +706,7 @@ TEXT ·mulNoADX(SB), NOSPLIT, $0-24
 	ADCQ $0x00, R12
 	ADCQ $0x00, BX
 
-	// | a2 * b1 
+	// | a2 * b1
 	MOVQ 8(SI), AX
 	MULQ CX
 	ADDQ AX, R11
@@ -850,28 +715,24 @@ TEXT ·mulNoADX(SB), NOSPLIT, $0-24
 	MOVQ $0x00, BX
 	ADCQ $0x00, BX
 
-	// | a2 * b2 
+	// | a2 * b2
 	MOVQ 16(SI), AX
 	MULQ CX
 	ADDQ AX, R12
 	ADCQ DX, R13
 	ADCQ BX, R14
 
-	// | a2 * b3 
+	// | a2 * b3
 	MOVQ 24(SI), AX
 	MULQ CX
 	ADDQ AX, R13
 	ADCQ DX, R14
 
-	// | 
-
-/* i = 3                                   */
-
 	// | a3 @ CX
 	MOVQ 24(DI), CX
 	MOVQ $0x00, BX
 
-	// | a3 * b0 
+	// | a3 * b0
 	MOVQ (SI), AX
 	MULQ CX
 	ADDQ AX, R11
@@ -879,7 +740,7 @@ TEXT ·mulNoADX(SB), NOSPLIT, $0-24
 	ADCQ $0x00, R13
 	ADCQ $0x00, BX
 
-	// | a3 * b1 
+	// | a3 * b1
 	MOVQ 8(SI), AX
 	MULQ CX
 	ADDQ AX, R12
@@ -888,64 +749,255 @@ TEXT ·mulNoADX(SB), NOSPLIT, $0-24
 	MOVQ $0x00, BX
 	ADCQ $0x00, BX
 
-	// | a3 * b2 
+	// | a3 * b2
 	MOVQ 16(SI), AX
 	MULQ CX
 	ADDQ AX, R13
 	ADCQ DX, R14
 	ADCQ $0x00, BX
 
-	// | a3 * b3 
+	// | a3 * b3
 	MOVQ 24(SI), AX
 	MULQ CX
 	ADDQ AX, R14
 	ADCQ DX, BX
 
-	// | 
+	// | montgomery reduction
+	// | u0 = w0 * inp
+	MOVQ R8, AX
+	MULQ ·inp+0(SB)
+	MOVQ AX, DI
+	MOVQ $0x00, CX
 
-/* 			                                     */
+	MOVQ ·modulus+0(SB), AX
+	MULQ DI
+	ADDQ AX, R8
+	ADCQ DX, CX
+	MOVQ ·modulus+8(SB), AX
+	MULQ DI
+	ADDQ AX, R9
+	ADCQ $0x00, DX
+	ADDQ CX, R9
+	MOVQ $0x00, CX
+	ADCQ DX, CX
+	MOVQ ·modulus+16(SB), AX
+	MULQ DI
+	ADDQ AX, R10
+	ADCQ $0x00, DX
+	ADDQ CX, R10
+	MOVQ $0x00, CX
+	ADCQ DX, CX
+	MOVQ ·modulus+24(SB), AX
+	MULQ DI
+	ADDQ AX, R11
+	ADCQ $0x00, DX
+	ADDQ CX, R11
+	ADCQ DX, R12
+	ADCQ $0x00, R8
 
-	// | 
-	// | W
-	// | 0   R8        | 1   R9        | 2   R10       | 3   R11       
-	// | 4   R12       | 5   R13       | 6   R14       | 7   BX        
+	// | u1 = w1 * inp
+	MOVQ R9, AX
+	MULQ ·inp+0(SB)
+	MOVQ AX, DI
+	MOVQ $0x00, CX
 
+	MOVQ ·modulus+0(SB), AX
+	MULQ DI
+	ADDQ AX, R9
+	ADCQ DX, CX
+	MOVQ ·modulus+8(SB), AX
+	MULQ DI
+	ADDQ AX, R10
+	ADCQ $0x00, DX
+	ADDQ CX, R10
+	MOVQ $0x00, CX
+	ADCQ DX, CX
+	MOVQ ·modulus+16(SB), AX
+	MULQ DI
+	ADDQ AX, R11
+	ADCQ $0x00, DX
+	ADDQ CX, R11
+	MOVQ $0x00, CX
+	ADCQ DX, CX
+	MOVQ ·modulus+24(SB), AX
+	MULQ DI
+	ADDQ AX, R12
+	ADCQ DX, R8
+	ADDQ CX, R12
+	ADCQ R8, R13
+	MOVQ $0x00, R8
+	ADCQ $0x00, R8
 
-	// | 
+	// | u2 = w2 * inp
+	MOVQ R10, AX
+	MULQ ·inp+0(SB)
+	MOVQ AX, DI
+	MOVQ $0x00, CX
 
-/* montgomery reduction                    */
+	MOVQ ·modulus+0(SB), AX
+	MULQ DI
+	ADDQ AX, R10
+	ADCQ DX, CX
+	MOVQ ·modulus+8(SB), AX
+	MULQ DI
+	ADDQ AX, R11
+	ADCQ $0x00, DX
+	ADDQ CX, R11
+	MOVQ $0x00, CX
+	ADCQ DX, CX
+	MOVQ ·modulus+16(SB), AX
+	MULQ DI
+	ADDQ AX, R12
+	ADCQ $0x00, DX
+	ADDQ CX, R12
+	MOVQ $0x00, CX
+	ADCQ DX, CX
+	MOVQ ·modulus+24(SB), AX
+	MULQ DI
+	ADDQ AX, R13
+	ADCQ DX, R8
+	ADDQ CX, R13
+	ADCQ R8, R14
+	MOVQ $0x00, R8
+	ADCQ $0x00, R8
 
-	// | 
+	// | u3 = w3 * inp
+	MOVQ R11, AX
+	MULQ ·inp+0(SB)
+	MOVQ AX, DI
+	MOVQ $0x00, CX
 
-/* i = 0                                   */
+	MOVQ ·modulus+0(SB), AX
+	MULQ DI
+	ADDQ AX, R11
+	ADCQ DX, CX
+	MOVQ ·modulus+8(SB), AX
+	MULQ DI
+	ADDQ AX, R12
+	ADCQ $0x00, DX
+	ADDQ CX, R12
+	MOVQ $0x00, CX
+	ADCQ DX, CX
+	MOVQ ·modulus+16(SB), AX
+	MULQ DI
+	ADDQ AX, R13
+	ADCQ $0x00, DX
+	ADDQ CX, R13
+	MOVQ $0x00, CX
+	ADCQ DX, CX
+	MOVQ ·modulus+24(SB), AX
+	MULQ DI
+	ADDQ AX, R14
+	ADCQ DX, R8
+	ADDQ CX, R14
+	ADCQ R8, BX
+	MOVQ $0x00, R8
+	ADCQ $0x00, R8
+
+	// | modular reduction
+	MOVQ R12, SI
+	SUBQ ·modulus+0(SB), SI
+	MOVQ R13, R9
+	SBBQ ·modulus+8(SB), R9
+	MOVQ R14, R10
+	SBBQ ·modulus+16(SB), R10
+	MOVQ BX, R11
+	SBBQ ·modulus+24(SB), R11
+	SBBQ $0x00, R8
+
+	MOVQ    c+0(FP), R8
+	CMOVQCC SI, R12
+	MOVQ    R12, (R8)
+	CMOVQCC R9, R13
+	MOVQ    R13, 8(R8)
+	CMOVQCC R10, R14
+	MOVQ    R14, 16(R8)
+	CMOVQCC R11, BX
+	MOVQ    BX, 24(R8)
+	RET
+
+// func squareNoADX(c *[4]uint64, a *[4]uint64)
+TEXT ·squareNoADX(SB), NOSPLIT, $0-16
+	MOVQ a+8(FP), DI
+
+	// | cross products
+	MOVQ (DI), CX
+	MOVQ 8(DI), AX
+	MULQ CX
+	MOVQ AX, R9
+	MOVQ DX, R10
+	MOVQ 16(DI), AX
+	MULQ CX
+	ADDQ AX, R10
+	ADCQ $0x00, DX
+	MOVQ DX, R11
+	MOVQ 24(DI), AX
+	MULQ CX
+	ADDQ AX, R11
+	ADCQ $0x00, DX
+	MOVQ DX, R12
+	MOVQ 8(DI), CX
+	MOVQ 16(DI), AX
+	MULQ CX
+	ADDQ AX, R11
+	ADCQ DX, R12
+	MOVQ $0x00, R13
+	ADCQ $0x00, R13
+	MOVQ 24(DI), AX
+	MULQ CX
+	ADDQ AX, R12
+	ADCQ DX, R13
+	MOVQ 16(DI), CX
+	MOVQ 24(DI), AX
+	MULQ CX
+	ADDQ AX, R13
+	ADCQ $0x00, DX
+	MOVQ DX, R14
 
-	// | 
-	// | W
-	// | 0   R8        | 1   R9        | 2   R10       | 3   R11       
-	// | 4   R12       | 5   R13       | 6   R14       | 7   BX        
+	// | double cross products
+	MOVQ $0x00, BX
+	ADDQ R9, R9
+	ADCQ R10, R10
+	ADCQ R11, R11
+	ADCQ R12, R12
+	ADCQ R13, R13
+	ADCQ R14, R14
+	ADCQ $0x00, BX
 
+	// | diagonal
+	MOVQ (DI), AX
+	MULQ AX
+	MOVQ AX, R8
+	MOVQ DX, CX
+	MOVQ 8(DI), AX
+	MULQ AX
+	ADDQ CX, R9
+	ADCQ AX, R10
+	ADCQ $0x00, DX
+	MOVQ DX, CX
+	MOVQ 16(DI), AX
+	MULQ AX
+	ADDQ CX, R11
+	ADCQ AX, R12
+	ADCQ $0x00, DX
+	MOVQ DX, CX
+	MOVQ 24(DI), AX
+	MULQ AX
+	ADDQ CX, R13
+	ADCQ AX, R14
+	ADCQ DX, BX
 
-	// | | u0 = w0 * inp
+	// | montgomery reduction
+	// | u0 = w0 * inp
 	MOVQ R8, AX
 	MULQ ·inp+0(SB)
 	MOVQ AX, DI
 	MOVQ $0x00, CX
 
-	// | 
-
-/*                                         */
-
-	// | j0
-
-	// | w0 @ R8
 	MOVQ ·modulus+0(SB), AX
 	MULQ DI
 	ADDQ AX, R8
 	ADCQ DX, CX
-
-	// | j1
-
-	// | w1 @ R9
 	MOVQ ·modulus+8(SB), AX
 	MULQ DI
 	ADDQ AX, R9
@@ -953,10 +1005,6 @@ TEXT ·mulNoADX(SB), NOSPLIT, $0-24
 	ADDQ CX, R9
 	MOVQ $0x00, CX
 	ADCQ DX, CX
-
-	// | j2
-
-	// | w2 @ R10
 	MOVQ ·modulus+16(SB), AX
 	MULQ DI
 	ADDQ AX, R10
@@ -964,51 +1012,24 @@ TEXT ·mulNoADX(SB), NOSPLIT, $0-24
 	ADDQ CX, R10
 	MOVQ $0x00, CX
 	ADCQ DX, CX
-
-	// | j3
-
-	// | w3 @ R11
 	MOVQ ·modulus+24(SB), AX
 	MULQ DI
 	ADDQ AX, R11
 	ADCQ $0x00, DX
 	ADDQ CX, R11
-
-	// | w4 @ R12
 	ADCQ DX, R12
 	ADCQ $0x00, R8
 
-	// | 
-
-/* i = 1                                   */
-
-	// | 
-	// | W
-	// | 0   -         | 1   R9        | 2   R10       | 3   R11       
-	// | 4   R12       | 5   R13       | 6   R14       | 7   BX        
-
-
-	// | | u1 = w1 * inp
+	// | u1 = w1 * inp
 	MOVQ R9, AX
 	MULQ ·inp+0(SB)
 	MOVQ AX, DI
 	MOVQ $0x00, CX
 
-	// | 
-
-/*                                         */
-
-	// | j0
-
-	// | w1 @ R9
 	MOVQ ·modulus+0(SB), AX
 	MULQ DI
 	ADDQ AX, R9
 	ADCQ DX, CX
-
-	// | j1
-
-	// | w2 @ R10
 	MOVQ ·modulus+8(SB), AX
 	MULQ DI
 	ADDQ AX, R10
@@ -1016,10 +1037,6 @@ TEXT ·mulNoADX(SB), NOSPLIT, $0-24
 	ADDQ CX, R10
 	MOVQ $0x00, CX
 	ADCQ DX, CX
-
-	// | j2
-
-	// | w3 @ R11
 	MOVQ ·modulus+16(SB), AX
 	MULQ DI
 	ADDQ AX, R11
@@ -1027,52 +1044,25 @@ TEXT ·mulNoADX(SB), NOSPLIT, $0-24
 	ADDQ CX, R11
 	MOVQ $0x00, CX
 	ADCQ DX, CX
-
-	// | j3
-
-	// | w4 @ R12
 	MOVQ ·modulus+24(SB), AX
 	MULQ DI
 	ADDQ AX, R12
 	ADCQ DX, R8
 	ADDQ CX, R12
-
-	// | w5 @ R13
 	ADCQ R8, R13
 	MOVQ $0x00, R8
 	ADCQ $0x00, R8
 
-	// | 
-
-/* i = 2                                   */
-
-	// | 
-	// | W
-	// | 0   -         | 1   -         | 2   R10       | 3   R11       
-	// | 4   R12       | 5   R13       | 6   R14       | 7   BX        
-
-
-	// | | u2 = w2 * inp
+	// | u2 = w2 * inp
 	MOVQ R10, AX
 	MULQ ·inp+0(SB)
 	MOVQ AX, DI
 	MOVQ $0x00, CX
 
-	// | 
-
-/*                                         */
-
-	// | j0
-
-	// | w2 @ R10
 	MOVQ ·modulus+0(SB), AX
 	MULQ DI
 	ADDQ AX, R10
 	ADCQ DX, CX
-
-	// | j1
-
-	// | w3 @ R11
 	MOVQ ·modulus+8(SB), AX
 	MULQ DI
 	ADDQ AX, R11
@@ -1080,10 +1070,6 @@ TEXT ·mulNoADX(SB), NOSPLIT, $0-24
 	ADDQ CX, R11
 	MOVQ $0x00, CX
 	ADCQ DX, CX
-
-	// | j2
-
-	// | w4 @ R12
 	MOVQ ·modulus+16(SB), AX
 	MULQ DI
 	ADDQ AX, R12
@@ -1091,52 +1077,25 @@ TEXT ·mulNoADX(SB), NOSPLIT, $0-24
 	ADDQ CX, R12
 	MOVQ $0x00, CX
 	ADCQ DX, CX
-
-	// | j3
-
-	// | w5 @ R13
 	MOVQ ·modulus+24(SB), AX
 	MULQ DI
 	ADDQ AX, R13
 	ADCQ DX, R8
 	ADDQ CX, R13
-
-	// | w6 @ R14
 	ADCQ R8, R14
 	MOVQ $0x00, R8
 	ADCQ $0x00, R8
 
-	// | 
-
-/* i = 3                                   */
-
-	// | 
-	// | W
-	// | 0   -         | 1   -         | 2   -         | 3   R11       
-	// | 4   R12       | 5   R13       | 6   R14       | 7   BX        
-
-
-	// | | u3 = w3 * inp
+	// | u3 = w3 * inp
 	MOVQ R11, AX
 	MULQ ·inp+0(SB)
 	MOVQ AX, DI
 	MOVQ $0x00, CX
 
-	// | 
-
-/*                                         */
-
-	// | j0
-
-	// | w3 @ R11
 	MOVQ ·modulus+0(SB), AX
 	MULQ DI
 	ADDQ AX, R11
 	ADCQ DX, CX
-
-	// | j1
-
-	// | w4 @ R12
 	MOVQ ·modulus+8(SB), AX
 	MULQ DI
 	ADDQ AX, R12
@@ -1144,10 +1103,6 @@ TEXT ·mulNoADX(SB), NOSPLIT, $0-24
 	ADDQ CX, R12
 	MOVQ $0x00, CX
 	ADCQ DX, CX
-
-	// | j2
-
-	// | w5 @ R13
 	MOVQ ·modulus+16(SB), AX
 	MULQ DI
 	ADDQ AX, R13
@@ -1155,31 +1110,16 @@ TEXT ·mulNoADX(SB), NOSPLIT, $0-24
 	ADDQ CX, R13
 	MOVQ $0x00, CX
 	ADCQ DX, CX
-
-	// | j3
-
-	// | w6 @ R14
 	MOVQ ·modulus+24(SB), AX
 	MULQ DI
 	ADDQ AX, R14
 	ADCQ DX, R8
 	ADDQ CX, R14
-
-	// | w-1 @ BX
 	ADCQ R8, BX
 	MOVQ $0x00, R8
 	ADCQ $0x00, R8
 
-	// | 
-	// | W montgomerry reduction ends
-	// | 0   -         | 1   -         | 2   -         | 3   -         
-	// | 4   R12       | 5   R13       | 6   R14       | 7   BX        
-
-
-	// | 
-
-/* modular reduction                       */
-
+	// | modular reduction
 	MOVQ R12, SI
 	SUBQ ·modulus+0(SB), SI
 	MOVQ R13, R9
@@ -1190,10 +1130,6 @@ TEXT ·mulNoADX(SB), NOSPLIT, $0-24
 	SBBQ ·modulus+24(SB), R11
 	SBBQ $0x00, R8
 
-	// | 
-
-/* out                                     */
-
 	MOVQ    c+0(FP), R8
 	CMOVQCC SI, R12
 	MOVQ    R12, (R8)
@@ -1204,7 +1140,3 @@ TEXT ·mulNoADX(SB), NOSPLIT, $0-24
 	CMOVQCC R11, BX
 	MOVQ    BX, 24(R8)
 	RET
-
-	// | 
-
-/* end                                     */
\ No newline at end of file
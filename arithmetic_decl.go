@@ -1,28 +1,23 @@
+//go:build amd64 && !generic
 // +build amd64,!generic
 
+// Code generated by fieldgen -modulus 0x30644e72e131a029b85045b68181585d97816a916871ca8d3c208c16d87cfd47 -type fe -output arithmetic -wide wfe. DO NOT EDIT.
+
 package bn254
 
 import "golang.org/x/sys/cpu"
 
+// ADX and BMI2 variants are the defaults, init falls back to the others where they are not available.
 func init() {
 	if !cpu.X86.HasADX || !cpu.X86.HasBMI2 {
 		mul = mulNoADX
+		square = squareNoADX
 	}
 }
 
-var mul func(c, a, b *fe) = mulNoADX
-
-func square(c, a *fe) {
-	mul(c, a, a)
-}
+var mul func(c, a, b *fe) = mulADX
 
-func neg(c, a *fe) {
-	if a.isZero() {
-		c.set(a)
-	} else {
-		_neg(c, a)
-	}
-}
+var square func(c, a *fe) = squareADX
 
 //go:noescape
 func add(c, a, b *fe)
@@ -43,7 +38,7 @@ func subAssign(a, b *fe)
 func lsubAssign(a, b *fe) uint64
 
 //go:noescape
-func _neg(c, a *fe)
+func neg(c, a *fe)
 
 //go:noescape
 func double(c, a *fe)
@@ -56,3 +51,9 @@ func mulNoADX(c, a, b *fe)
 
 //go:noescape
 func mulADX(c, a, b *fe)
+
+//go:noescape
+func squareNoADX(c, a *fe)
+
+//go:noescape
+func squareADX(c, a *fe)
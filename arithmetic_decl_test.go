@@ -0,0 +1,91 @@
+//go:build amd64 && !generic
+// +build amd64,!generic
+
+package bn254
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"golang.org/x/sys/cpu"
+)
+
+func TestFpSquareAssembly(t *testing.T) {
+	hasADX := cpu.X86.HasADX && cpu.X86.HasBMI2
+	// montgomery square of a is a^2 * R^-1 mod p
+	rInv := new(big.Int).Lsh(big.NewInt(1), 256)
+	rInv.ModInverse(rInv, modulus.big())
+	expected := func(a *fe) *big.Int {
+		r := a.big()
+		r.Mul(r, r).Mul(r, rInv)
+		return r.Mod(r, modulus.big())
+	}
+	check := func(a *fe) {
+		e := expected(a)
+		c, d := new(fe), new(fe)
+		squareNoADX(c, a)
+		if c.big().Cmp(e) != 0 {
+			t.Fatal("squareNoADX is not satisfied")
+		}
+		// dispatched square and mul agree with each other
+		square(c, a)
+		mul(d, a, a)
+		if !c.equal(d) {
+			t.Fatal("square and mul disagree")
+		}
+		mulNoADX(c, a, a)
+		if c.big().Cmp(e) != 0 {
+			t.Fatal("mulNoADX is not satisfied")
+		}
+		if !hasADX {
+			return
+		}
+		squareADX(c, a)
+		if c.big().Cmp(e) != 0 {
+			t.Fatal("squareADX is not satisfied")
+		}
+		mulADX(c, a, a)
+		if c.big().Cmp(e) != 0 {
+			t.Fatal("mulADX is not satisfied")
+		}
+	}
+	pMinusOne := new(fe).set(&modulus)
+	pMinusOne[0]--
+	edges := []*fe{
+		new(fe).zero(),
+		&fe{1, 0, 0, 0},
+		&fe{^uint64(0), 0, 0, 0},
+		&fe{^uint64(0), ^uint64(0), 0, 0},
+		&fe{0, 0, 0, 1},
+		pMinusOne,
+		new(fe).one(),
+		new(fe).set(r2),
+	}
+	for _, a := range edges {
+		check(a)
+	}
+	for i := 0; i < fuz; i++ {
+		a, _ := new(fe).rand(rand.Reader)
+		check(a)
+	}
+}
+
+func BenchmarkFpMul(t *testing.B) {
+	a, _ := new(fe).rand(rand.Reader)
+	b, _ := new(fe).rand(rand.Reader)
+	c := new(fe)
+	t.ResetTimer()
+	for i := 0; i < t.N; i++ {
+		mul(c, a, b)
+	}
+}
+
+func BenchmarkFpSquare(t *testing.B) {
+	a, _ := new(fe).rand(rand.Reader)
+	c := new(fe)
+	t.ResetTimer()
+	for i := 0; i < t.N; i++ {
+		square(c, a)
+	}
+}
@@ -1,338 +1,168 @@
+//go:build !amd64 || generic
 // +build !amd64 generic
 
-package bn254
-
-import (
-	"math/bits"
-)
+// Code generated by fieldgen -modulus 0x30644e72e131a029b85045b68181585d97816a916871ca8d3c208c16d87cfd47 -type fe -output arithmetic -wide wfe. DO NOT EDIT.
 
-// *_ops_no_asm.go
+package bn254
 
-// Copyright 2020 ConsenSys Software Inc.
-//
-// Licensed under the Apache License, Version 2.0 (the "License");
-// you may not use this file except in compliance with the License.
-// You may obtain a copy of the License at
-//
-//     http://www.apache.org/licenses/LICENSE-2.0
-//
-// Unless required by applicable law or agreed to in writing, software
-// distributed under the License is distributed on an "AS IS" BASIS,
-// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
-// See the License for the specific language governing permissions and
-// limitations under the License.
+import "math/bits"
 
-// Code generated by goff (v0.3.5) DO NOT EDIT
+// Generic versions of field arithmetic. Reductions select results with masks
+// rather than branches.
 
 func add(z, x, y *fe) {
-	var carry uint64
-
-	z[0], carry = bits.Add64(x[0], y[0], 0)
-	z[1], carry = bits.Add64(x[1], y[1], carry)
-	z[2], carry = bits.Add64(x[2], y[2], carry)
-	z[3], _ = bits.Add64(x[3], y[3], carry)
-
-	// if z > q --> z -= q
-	// note: this is NOT constant time
-	if !(z[3] < 3486998266802970665 || (z[3] == 3486998266802970665 && (z[2] < 13281191951274694749 || (z[2] == 13281191951274694749 && (z[1] < 10917124144477883021 || (z[1] == 10917124144477883021 && (z[0] < 4332616871279656263))))))) {
-		var b uint64
-		z[0], b = bits.Sub64(z[0], 4332616871279656263, 0)
-		z[1], b = bits.Sub64(z[1], 10917124144477883021, b)
-		z[2], b = bits.Sub64(z[2], 13281191951274694749, b)
-		z[3], _ = bits.Sub64(z[3], 3486998266802970665, b)
-	}
+	var c, b uint64
+	var t, s [4]uint64
+	t[0], c = bits.Add64(x[0], y[0], 0)
+	t[1], c = bits.Add64(x[1], y[1], c)
+	t[2], c = bits.Add64(x[2], y[2], c)
+	t[3], c = bits.Add64(x[3], y[3], c)
+	s[0], b = bits.Sub64(t[0], 0x3c208c16d87cfd47, 0)
+	s[1], b = bits.Sub64(t[1], 0x97816a916871ca8d, b)
+	s[2], b = bits.Sub64(t[2], 0xb85045b68181585d, b)
+	s[3], b = bits.Sub64(t[3], 0x30644e72e131a029, b)
+	_, b = bits.Sub64(c, 0, b)
+	// keep t - p if it does not borrow
+	mask := b - 1
+	z[0] = (s[0] & mask) | (t[0] &^ mask)
+	z[1] = (s[1] & mask) | (t[1] &^ mask)
+	z[2] = (s[2] & mask) | (t[2] &^ mask)
+	z[3] = (s[3] & mask) | (t[3] &^ mask)
 }
 
 func addAssign(z, y *fe) {
-	var carry uint64
-
-	z[0], carry = bits.Add64(z[0], y[0], 0)
-	z[1], carry = bits.Add64(z[1], y[1], carry)
-	z[2], carry = bits.Add64(z[2], y[2], carry)
-	z[3], _ = bits.Add64(z[3], y[3], carry)
-
-	// if z > q --> z -= q
-	// note: this is NOT constant time
-	if !(z[3] < 3486998266802970665 || (z[3] == 3486998266802970665 && (z[2] < 13281191951274694749 || (z[2] == 13281191951274694749 && (z[1] < 10917124144477883021 || (z[1] == 10917124144477883021 && (z[0] < 4332616871279656263))))))) {
-		var b uint64
-		z[0], b = bits.Sub64(z[0], 4332616871279656263, 0)
-		z[1], b = bits.Sub64(z[1], 10917124144477883021, b)
-		z[2], b = bits.Sub64(z[2], 13281191951274694749, b)
-		z[3], _ = bits.Sub64(z[3], 3486998266802970665, b)
-	}
+	add(z, z, y)
 }
 
 func laddAssign(z, y *fe) {
-	var carry uint64
-
-	z[0], carry = bits.Add64(z[0], y[0], 0)
-	z[1], carry = bits.Add64(z[1], y[1], carry)
-	z[2], carry = bits.Add64(z[2], y[2], carry)
-	z[3], _ = bits.Add64(z[3], y[3], carry)
+	var c uint64
+	z[0], c = bits.Add64(z[0], y[0], 0)
+	z[1], c = bits.Add64(z[1], y[1], c)
+	z[2], c = bits.Add64(z[2], y[2], c)
+	z[3], _ = bits.Add64(z[3], y[3], c)
 }
 
 func double(z, x *fe) {
-	var carry uint64
-
-	z[0], carry = bits.Add64(x[0], x[0], 0)
-	z[1], carry = bits.Add64(x[1], x[1], carry)
-	z[2], carry = bits.Add64(x[2], x[2], carry)
-	z[3], _ = bits.Add64(x[3], x[3], carry)
-
-	// if z > q --> z -= q
-	// note: this is NOT constant time
-	if !(z[3] < 3486998266802970665 || (z[3] == 3486998266802970665 && (z[2] < 13281191951274694749 || (z[2] == 13281191951274694749 && (z[1] < 10917124144477883021 || (z[1] == 10917124144477883021 && (z[0] < 4332616871279656263))))))) {
-		var b uint64
-		z[0], b = bits.Sub64(z[0], 4332616871279656263, 0)
-		z[1], b = bits.Sub64(z[1], 10917124144477883021, b)
-		z[2], b = bits.Sub64(z[2], 13281191951274694749, b)
-		z[3], _ = bits.Sub64(z[3], 3486998266802970665, b)
-	}
+	add(z, x, x)
 }
 
 func doubleAssign(z *fe) {
-	var carry uint64
-
-	z[0], carry = bits.Add64(z[0], z[0], 0)
-	z[1], carry = bits.Add64(z[1], z[1], carry)
-	z[2], carry = bits.Add64(z[2], z[2], carry)
-	z[3], _ = bits.Add64(z[3], z[3], carry)
-
-	// if z > q --> z -= q
-	// note: this is NOT constant time
-	if !(z[3] < 3486998266802970665 || (z[3] == 3486998266802970665 && (z[2] < 13281191951274694749 || (z[2] == 13281191951274694749 && (z[1] < 10917124144477883021 || (z[1] == 10917124144477883021 && (z[0] < 4332616871279656263))))))) {
-		var b uint64
-		z[0], b = bits.Sub64(z[0], 4332616871279656263, 0)
-		z[1], b = bits.Sub64(z[1], 10917124144477883021, b)
-		z[2], b = bits.Sub64(z[2], 13281191951274694749, b)
-		z[3], _ = bits.Sub64(z[3], 3486998266802970665, b)
-	}
+	add(z, z, z)
 }
 
 func sub(z, x, y *fe) {
-	var b uint64
-	z[0], b = bits.Sub64(x[0], y[0], 0)
-	z[1], b = bits.Sub64(x[1], y[1], b)
-	z[2], b = bits.Sub64(x[2], y[2], b)
-	z[3], b = bits.Sub64(x[3], y[3], b)
-	if b != 0 {
-		var c uint64
-		z[0], c = bits.Add64(z[0], 4332616871279656263, 0)
-		z[1], c = bits.Add64(z[1], 10917124144477883021, c)
-		z[2], c = bits.Add64(z[2], 13281191951274694749, c)
-		z[3], _ = bits.Add64(z[3], 3486998266802970665, c)
-	}
+	var b, c uint64
+	var t [4]uint64
+	t[0], b = bits.Sub64(x[0], y[0], 0)
+	t[1], b = bits.Sub64(x[1], y[1], b)
+	t[2], b = bits.Sub64(x[2], y[2], b)
+	t[3], b = bits.Sub64(x[3], y[3], b)
+	// add p back if subtraction borrows
+	mask := -b
+	z[0], c = bits.Add64(t[0], 0x3c208c16d87cfd47&mask, 0)
+	z[1], c = bits.Add64(t[1], 0x97816a916871ca8d&mask, c)
+	z[2], c = bits.Add64(t[2], 0xb85045b68181585d&mask, c)
+	z[3], _ = bits.Add64(t[3], 0x30644e72e131a029&mask, c)
 }
 
 func subAssign(z, y *fe) {
-	var b uint64
-	z[0], b = bits.Sub64(z[0], y[0], 0)
-	z[1], b = bits.Sub64(z[1], y[1], b)
-	z[2], b = bits.Sub64(z[2], y[2], b)
-	z[3], b = bits.Sub64(z[3], y[3], b)
-	if b != 0 {
-		var c uint64
-		z[0], c = bits.Add64(z[0], 4332616871279656263, 0)
-		z[1], c = bits.Add64(z[1], 10917124144477883021, c)
-		z[2], c = bits.Add64(z[2], 13281191951274694749, c)
-		z[3], _ = bits.Add64(z[3], 3486998266802970665, c)
-	}
+	sub(z, z, y)
 }
 
-func lsubAssign(z, y *fe) {
+func lsubAssign(z, y *fe) uint64 {
 	var b uint64
 	z[0], b = bits.Sub64(z[0], y[0], 0)
 	z[1], b = bits.Sub64(z[1], y[1], b)
 	z[2], b = bits.Sub64(z[2], y[2], b)
 	z[3], b = bits.Sub64(z[3], y[3], b)
+	return b
 }
 
 func neg(z, x *fe) {
-	if x.isZero() {
-		z.zero()
-		return
-	}
-	var borrow uint64
-	z[0], borrow = bits.Sub64(4332616871279656263, x[0], 0)
-	z[1], borrow = bits.Sub64(10917124144477883021, x[1], borrow)
-	z[2], borrow = bits.Sub64(13281191951274694749, x[2], borrow)
-	z[3], _ = bits.Sub64(3486998266802970665, x[3], borrow)
+	var b uint64
+	var t [4]uint64
+	t[0], b = bits.Sub64(0x3c208c16d87cfd47, x[0], 0)
+	t[1], b = bits.Sub64(0x97816a916871ca8d, x[1], b)
+	t[2], b = bits.Sub64(0xb85045b68181585d, x[2], b)
+	t[3], _ = bits.Sub64(0x30644e72e131a029, x[3], b)
+	// zero stays zero
+	nz := x[0] | x[1] | x[2] | x[3]
+	mask := -((nz | -nz) >> 63)
+	z[0] = t[0] & mask
+	z[1] = t[1] & mask
+	z[2] = t[2] & mask
+	z[3] = t[3] & mask
 }
 
-// *.go
-
-// Copyright 2020 ConsenSys Software Inc.
-//
-// Licensed under the Apache License, Version 2.0 (the "License");
-// you may not use this file except in compliance with the License.
-// You may obtain a copy of the License at
-//
-//     http://www.apache.org/licenses/LICENSE-2.0
-//
-// Unless required by applicable law or agreed to in writing, software
-// distributed under the License is distributed on an "AS IS" BASIS,
-// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
-// See the License for the specific language governing permissions and
-// limitations under the License.
-
-// Code generated by goff (v0.3.5) DO NOT EDIT
-
-// Generic (no ADX instructions, no AMD64) versions of multiplication and squaring algorithms
-
 func mul(z, x, y *fe) {
-
 	var t [4]uint64
 	var c [3]uint64
 	{
 		// round 0
 		v := x[0]
 		c[1], c[0] = bits.Mul64(v, y[0])
-		m := c[0] * 9786893198990664585
-		c[2] = madd0(m, 4332616871279656263, c[0])
+		m := c[0] * 0x87d20782e4866389
+		c[2] = madd0(m, 0x3c208c16d87cfd47, c[0])
 		c[1], c[0] = madd1(v, y[1], c[1])
-		c[2], t[0] = madd2(m, 10917124144477883021, c[2], c[0])
+		c[2], t[0] = madd2(m, 0x97816a916871ca8d, c[2], c[0])
 		c[1], c[0] = madd1(v, y[2], c[1])
-		c[2], t[1] = madd2(m, 13281191951274694749, c[2], c[0])
+		c[2], t[1] = madd2(m, 0xb85045b68181585d, c[2], c[0])
 		c[1], c[0] = madd1(v, y[3], c[1])
-		t[3], t[2] = madd3(m, 3486998266802970665, c[0], c[2], c[1])
+		t[3], t[2] = madd3(m, 0x30644e72e131a029, c[0], c[2], c[1])
 	}
 	{
 		// round 1
 		v := x[1]
 		c[1], c[0] = madd1(v, y[0], t[0])
-		m := c[0] * 9786893198990664585
-		c[2] = madd0(m, 4332616871279656263, c[0])
+		m := c[0] * 0x87d20782e4866389
+		c[2] = madd0(m, 0x3c208c16d87cfd47, c[0])
 		c[1], c[0] = madd2(v, y[1], c[1], t[1])
-		c[2], t[0] = madd2(m, 10917124144477883021, c[2], c[0])
+		c[2], t[0] = madd2(m, 0x97816a916871ca8d, c[2], c[0])
 		c[1], c[0] = madd2(v, y[2], c[1], t[2])
-		c[2], t[1] = madd2(m, 13281191951274694749, c[2], c[0])
+		c[2], t[1] = madd2(m, 0xb85045b68181585d, c[2], c[0])
 		c[1], c[0] = madd2(v, y[3], c[1], t[3])
-		t[3], t[2] = madd3(m, 3486998266802970665, c[0], c[2], c[1])
+		t[3], t[2] = madd3(m, 0x30644e72e131a029, c[0], c[2], c[1])
 	}
 	{
 		// round 2
 		v := x[2]
 		c[1], c[0] = madd1(v, y[0], t[0])
-		m := c[0] * 9786893198990664585
-		c[2] = madd0(m, 4332616871279656263, c[0])
+		m := c[0] * 0x87d20782e4866389
+		c[2] = madd0(m, 0x3c208c16d87cfd47, c[0])
 		c[1], c[0] = madd2(v, y[1], c[1], t[1])
-		c[2], t[0] = madd2(m, 10917124144477883021, c[2], c[0])
+		c[2], t[0] = madd2(m, 0x97816a916871ca8d, c[2], c[0])
 		c[1], c[0] = madd2(v, y[2], c[1], t[2])
-		c[2], t[1] = madd2(m, 13281191951274694749, c[2], c[0])
+		c[2], t[1] = madd2(m, 0xb85045b68181585d, c[2], c[0])
 		c[1], c[0] = madd2(v, y[3], c[1], t[3])
-		t[3], t[2] = madd3(m, 3486998266802970665, c[0], c[2], c[1])
+		t[3], t[2] = madd3(m, 0x30644e72e131a029, c[0], c[2], c[1])
 	}
 	{
 		// round 3
 		v := x[3]
 		c[1], c[0] = madd1(v, y[0], t[0])
-		m := c[0] * 9786893198990664585
-		c[2] = madd0(m, 4332616871279656263, c[0])
+		m := c[0] * 0x87d20782e4866389
+		c[2] = madd0(m, 0x3c208c16d87cfd47, c[0])
 		c[1], c[0] = madd2(v, y[1], c[1], t[1])
-		c[2], z[0] = madd2(m, 10917124144477883021, c[2], c[0])
+		c[2], z[0] = madd2(m, 0x97816a916871ca8d, c[2], c[0])
 		c[1], c[0] = madd2(v, y[2], c[1], t[2])
-		c[2], z[1] = madd2(m, 13281191951274694749, c[2], c[0])
+		c[2], z[1] = madd2(m, 0xb85045b68181585d, c[2], c[0])
 		c[1], c[0] = madd2(v, y[3], c[1], t[3])
-		z[3], z[2] = madd3(m, 3486998266802970665, c[0], c[2], c[1])
-	}
-
-	// if z > q --> z -= q
-	// note: this is NOT constant time
-	if !(z[3] < 3486998266802970665 || (z[3] == 3486998266802970665 && (z[2] < 13281191951274694749 || (z[2] == 13281191951274694749 && (z[1] < 10917124144477883021 || (z[1] == 10917124144477883021 && (z[0] < 4332616871279656263))))))) {
-		var b uint64
-		z[0], b = bits.Sub64(z[0], 4332616871279656263, 0)
-		z[1], b = bits.Sub64(z[1], 10917124144477883021, b)
-		z[2], b = bits.Sub64(z[2], 13281191951274694749, b)
-		z[3], _ = bits.Sub64(z[3], 3486998266802970665, b)
-	}
+		z[3], z[2] = madd3(m, 0x30644e72e131a029, c[0], c[2], c[1])
+	}
+	// keep z - p if it does not borrow
+	var s [4]uint64
+	var k uint64
+	s[0], k = bits.Sub64(z[0], 0x3c208c16d87cfd47, 0)
+	s[1], k = bits.Sub64(z[1], 0x97816a916871ca8d, k)
+	s[2], k = bits.Sub64(z[2], 0xb85045b68181585d, k)
+	s[3], k = bits.Sub64(z[3], 0x30644e72e131a029, k)
+	mask := k - 1
+	z[0] = (s[0] & mask) | (z[0] &^ mask)
+	z[1] = (s[1] & mask) | (z[1] &^ mask)
+	z[2] = (s[2] & mask) | (z[2] &^ mask)
+	z[3] = (s[3] & mask) | (z[3] &^ mask)
 }
 
-func square(z, x *fe) {
-
-	var t [4]uint64
-	var c [3]uint64
-	{
-		// round 0
-		v := x[0]
-		c[1], c[0] = bits.Mul64(v, x[0])
-		m := c[0] * 9786893198990664585
-		c[2] = madd0(m, 4332616871279656263, c[0])
-		c[1], c[0] = madd1(v, x[1], c[1])
-		c[2], t[0] = madd2(m, 10917124144477883021, c[2], c[0])
-		c[1], c[0] = madd1(v, x[2], c[1])
-		c[2], t[1] = madd2(m, 13281191951274694749, c[2], c[0])
-		c[1], c[0] = madd1(v, x[3], c[1])
-		t[3], t[2] = madd3(m, 3486998266802970665, c[0], c[2], c[1])
-	}
-	{
-		// round 1
-		v := x[1]
-		c[1], c[0] = madd1(v, x[0], t[0])
-		m := c[0] * 9786893198990664585
-		c[2] = madd0(m, 4332616871279656263, c[0])
-		c[1], c[0] = madd2(v, x[1], c[1], t[1])
-		c[2], t[0] = madd2(m, 10917124144477883021, c[2], c[0])
-		c[1], c[0] = madd2(v, x[2], c[1], t[2])
-		c[2], t[1] = madd2(m, 13281191951274694749, c[2], c[0])
-		c[1], c[0] = madd2(v, x[3], c[1], t[3])
-		t[3], t[2] = madd3(m, 3486998266802970665, c[0], c[2], c[1])
-	}
-	{
-		// round 2
-		v := x[2]
-		c[1], c[0] = madd1(v, x[0], t[0])
-		m := c[0] * 9786893198990664585
-		c[2] = madd0(m, 4332616871279656263, c[0])
-		c[1], c[0] = madd2(v, x[1], c[1], t[1])
-		c[2], t[0] = madd2(m, 10917124144477883021, c[2], c[0])
-		c[1], c[0] = madd2(v, x[2], c[1], t[2])
-		c[2], t[1] = madd2(m, 13281191951274694749, c[2], c[0])
-		c[1], c[0] = madd2(v, x[3], c[1], t[3])
-		t[3], t[2] = madd3(m, 3486998266802970665, c[0], c[2], c[1])
-	}
-	{
-		// round 3
-		v := x[3]
-		c[1], c[0] = madd1(v, x[0], t[0])
-		m := c[0] * 9786893198990664585
-		c[2] = madd0(m, 4332616871279656263, c[0])
-		c[1], c[0] = madd2(v, x[1], c[1], t[1])
-		c[2], z[0] = madd2(m, 10917124144477883021, c[2], c[0])
-		c[1], c[0] = madd2(v, x[2], c[1], t[2])
-		c[2], z[1] = madd2(m, 13281191951274694749, c[2], c[0])
-		c[1], c[0] = madd2(v, x[3], c[1], t[3])
-		z[3], z[2] = madd3(m, 3486998266802970665, c[0], c[2], c[1])
-	}
-
-	// if z > q --> z -= q
-	// note: this is NOT constant time
-	if !(z[3] < 3486998266802970665 || (z[3] == 3486998266802970665 && (z[2] < 13281191951274694749 || (z[2] == 13281191951274694749 && (z[1] < 10917124144477883021 || (z[1] == 10917124144477883021 && (z[0] < 4332616871279656263))))))) {
-		var b uint64
-		z[0], b = bits.Sub64(z[0], 4332616871279656263, 0)
-		z[1], b = bits.Sub64(z[1], 10917124144477883021, b)
-		z[2], b = bits.Sub64(z[2], 13281191951274694749, b)
-		z[3], _ = bits.Sub64(z[3], 3486998266802970665, b)
-	}
-}
-
-// arith.go
-
-// Copyright 2020 ConsenSys Software Inc.
-//
-// Licensed under the Apache License, Version 2.0 (the "License");
-// you may not use this file except in compliance with the License.
-// You may obtain a copy of the License at
-//
-//     http://www.apache.org/licenses/LICENSE-2.0
-//
-// Unless required by applicable law or agreed to in writing, software
-// distributed under the License is distributed on an "AS IS" BASIS,
-// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
-// See the License for the specific language governing permissions and
-// limitations under the License.
-
-// Code generated by goff (v0.3.5) DO NOT EDIT
-
 // madd0 hi = a*b + c (discards lo bits)
 func madd0(a, b, c uint64) (hi uint64) {
 	var carry, lo uint64
@@ -362,6 +192,7 @@ func madd2(a, b, c, d uint64) (hi uint64, lo uint64) {
 	return
 }
 
+// madd3 hi, lo = a*b + c + d + e*2^64
 func madd3(a, b, c, d, e uint64) (hi uint64, lo uint64) {
 	var carry uint64
 	hi, lo = bits.Mul64(a, b)
@@ -371,3 +202,7 @@ func madd3(a, b, c, d, e uint64) (hi uint64, lo uint64) {
 	hi, _ = bits.Add64(hi, e, carry)
 	return
 }
+
+func square(z, x *fe) {
+	mul(z, x, x)
+}
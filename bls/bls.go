@@ -11,6 +11,9 @@ import (
 
 var Order = bn254.Order
 
+// g2One is the prepared G2 generator which is paired with every signature
+var g2One = bn254.NewEngine().PrepareG2(bn254.NewG2().One())
+
 type PointG1 = bn254.PointG1 // 32 * 2 bytes -> signature
 type PointG2 = bn254.PointG2 // 32 * 4 bytes -> pubkey
 
@@ -163,13 +166,13 @@ func AggregateSignatures(signatures []*Signature) *AggregatedSignature {
 
 func (verifier *BLSVerifier) Verify(message Message, signature *Signature, publicKey *PublicKey) (bool, error) {
 	e := bn254.NewEngine()
-	g1, g2 := e.G1, e.G2
+	g1 := e.G1
 	M, err := g1.HashToCurveFT(message, verifier.Domain)
 	if err != nil {
 		return false, err
 	}
 	e.AddPair(M, publicKey.point)
-	e.AddPairInv(signature.point, g2.One())
+	e.AddPairPreparedInv(signature.point, g2One)
 	return e.Check(), nil
 }
 
@@ -178,14 +181,14 @@ func (verifier *BLSVerifier) VerifyAggregateCommon(message Message, publicKeys [
 		return false, errors.New("public key size is zero")
 	}
 	e := bn254.NewEngine()
-	g1, g2 := e.G1, e.G2
+	g1 := e.G1
 	M, err := g1.HashToCurveFT(message, verifier.Domain)
 	if err != nil {
 		return false, err
 	}
 	aggregatedPublicKeys := AggregatePublicKeys(publicKeys)
 	e.AddPair(M, aggregatedPublicKeys.point)
-	e.AddPairInv(signature.point, g2.One())
+	e.AddPairPreparedInv(signature.point, g2One)
 	return e.Check(), nil
 }
 
@@ -197,8 +200,8 @@ func (verifier *BLSVerifier) VerifyAggregate(messages []Message, publicKeys []*P
 		return false, errors.New("message and key sizes must be equal")
 	}
 	e := bn254.NewEngine()
-	g1, g2 := e.G1, e.G2
-	e.AddPairInv(signature.point, g2.One())
+	g1 := e.G1
+	e.AddPairPreparedInv(signature.point, g2One)
 	for i := 0; i < len(messages); i++ {
 		M, err := g1.HashToCurveFT(messages[i], verifier.Domain)
 		if err != nil {
@@ -206,5 +209,5 @@ func (verifier *BLSVerifier) VerifyAggregate(messages []Message, publicKeys []*P
 		}
 		e.AddPair(M, publicKeys[i].point)
 	}
-	return e.Check(), nil
+	return e.CheckParallel(0), nil
 }
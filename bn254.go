@@ -3,13 +3,22 @@ package bn254
 import "math/big"
 
 func bigFromHex(hex string) *big.Int {
+	neg := len(hex) > 0 && hex[0] == '-'
+	if neg {
+		hex = hex[1:]
+	}
 	if len(hex) > 1 && hex[:2] == "0x" {
 		hex = hex[2:]
 	}
 	n, _ := new(big.Int).SetString(hex, 16)
+	if neg {
+		n.Neg(n)
+	}
 	return n
 }
 
+//go:generate go run ./internal/fieldgen -modulus 0x30644e72e131a029b85045b68181585d97816a916871ca8d3c208c16d87cfd47 -type fe -output arithmetic -wide wfe
+
 var inp uint64 = 9786893198990664585
 
 var modulus = fe{0x3c208c16d87cfd47, 0x97816a916871ca8d, 0xb85045b68181585d, 0x30644e72e131a029}
@@ -32,6 +41,8 @@ var pMinus3Over4 = bigFromHex("0xc19139cb84c680a6e14116da060561765e05aa45a1c72a3
 
 var pPlus1Over4 = bigFromHex("0xc19139cb84c680a6e14116da060561765e05aa45a1c72a34f082305b61f3f52")
 
+var pMinus2 = bigFromHex("0x30644e72e131a029b85045b68181585d97816a916871ca8d3c208c16d87cfd45")
+
 var pMinus1Over2 = bigFromHex("0x183227397098d014dc2822db40c0ac2ecbc0b548b438e5469e10460b6c3e7ea3")
 
 var pMinus1Over2Fe = &fe{0x9e10460b6c3e7ea3, 0xcbc0b548b438e546, 0xdc2822db40c0ac2e, 0x183227397098d014}
@@ -63,6 +74,20 @@ var sqrtMinus3 = &fe{0x3e424383c39ad5b9, 0x28ed3f00245fdc6a, 0x4a2e7e8c1e5ebdfa,
 // (sqrt(-3) - 1) / 2
 var zz = &fe{0x71930c11d782e155, 0xa6bb947cffbe3323, 0xaa303344d4741444, 0x2c3b3f0d26594943}
 
+// Shallue-van de Woestijne map constants for Z = 1
+
+// g(Z) = 4
+var svdwC1 = &fe{0x115482203dbf392d, 0x926242126eaa626a, 0xe16a48076063c052, 0x07c5909386eddc93}
+
+// -Z / 2
+var svdwC2 = &fe{0xb461a4448976f7d5, 0xc6843fb439555fa7, 0x28f0d12384840918, 0x112ceb58a394e07d}
+
+// sqrt(-g(Z) * 3 * Z^2) with sgn0 equal to zero
+var svdwC3 = &fe{0x7c8487078735ab72, 0x51da7e0048bfb8d4, 0x945cfd183cbd7bf4, 0x0b70b1ec48ae62c6}
+
+// -4 * g(Z) / (3 * Z^2)
+var svdwC4 = &fe{0xa79a2bdca0800831, 0x19fd7617e49815a1, 0xbb8d0c885550c7b1, 0x05c4aeb6ec7e0f48}
+
 // Curve constants
 
 // Group order
@@ -110,11 +135,36 @@ var u = bigFromHex("0x44e992b44a6909f1")
 
 var sixUPlus2 = bigFromHex("0x19d797039be763ba8")
 
+// 6 * u^2 which is equal to p - q
+var sixUSquare = bigFromHex("0x6f4d8248eeb859fbf83e9682e87cfd46")
+
 var nonResidueInPMinusOver2 = fe2{
 	fe{0xe4bbdd0c2936b629, 0xbb30f162e133bacb, 0x31a9d1b6f9645366, 0x253570bea500f8dd},
 	fe{0xa1d77ce45ffe77c7, 0x07affd117826d1db, 0x6d16bd27bb7edc6b, 0x2c87200285defecc},
 }
 
+// gtDecompositionBasis is a reduced basis of the lattice of vectors (a0, a1, a2, a3)
+// such that a0 + a1 * p + a2 * p^2 + a3 * p^3 = 0 mod q, where p = 6u^2 mod q.
+// b0 = (2u + 1, 0, 2u, 1)
+// b1 = (2u, u + 1, -u, u)
+// b2 = (u + 1, u, u, -2u)
+// b3 = (2u + 1, -u, -u - 1, -u)
+var gtDecompositionBasis = [4][4]*big.Int{
+	{bigFromHex("0x89d3256894d213e3"), bigFromHex("0x0"), bigFromHex("0x89d3256894d213e2"), bigFromHex("0x1")},
+	{bigFromHex("0x89d3256894d213e2"), bigFromHex("0x44e992b44a6909f2"), bigFromHex("-0x44e992b44a6909f1"), bigFromHex("0x44e992b44a6909f1")},
+	{bigFromHex("0x44e992b44a6909f2"), bigFromHex("0x44e992b44a6909f1"), bigFromHex("0x44e992b44a6909f1"), bigFromHex("-0x89d3256894d213e2")},
+	{bigFromHex("0x89d3256894d213e3"), bigFromHex("-0x44e992b44a6909f1"), bigFromHex("-0x44e992b44a6909f2"), bigFromHex("-0x44e992b44a6909f1")},
+}
+
+// gtDecompositionConstants is the first row of inverse of gtDecompositionBasis multiplied by q.
+var gtDecompositionConstants = [4]*big.Int{
+	bigFromHex("0x1df623ef8af183e446fb76a5e4491ec4d8378506dd96f60e"),
+	bigFromHex("0x1df623ef8af183e3d7adf45cf590c4c8113c366715dedaf5"),
+	bigFromHex("0x89d3256894d213e3"),
+	bigFromHex("0x1df623ef8af183e446fb76a5e4491ec4934df252932dec1d"),
+}
+
+// frobeniusCoeffs61[k] = nonResidue2^((p^k - 1) / 3)
 var frobeniusCoeffs61 = [6]fe2{
 	fe2{
 		fe{0xd35d438dc58f0d9d, 0x0a78eb28f5c70b3d, 0x666ea36f7879462c, 0x0e0a77c19a07df2f},
@@ -133,15 +183,16 @@ var frobeniusCoeffs61 = [6]fe2{
 		fe{0xacc02860f7ce93ac, 0x3933d5817ba76b4c, 0x69e6188b446c8467, 0x0a46036d4417cc55},
 	},
 	fe2{
-		fe{0x0000000000000000, 0x0000000000000000, 0x0000000000000000, 0x0000000000000000},
+		fe{0x71930c11d782e155, 0xa6bb947cffbe3323, 0xaa303344d4741444, 0x2c3b3f0d26594943},
 		fe{0x0000000000000000, 0x0000000000000000, 0x0000000000000000, 0x0000000000000000},
 	},
 	fe2{
-		fe{0x0000000000000000, 0x0000000000000000, 0x0000000000000000, 0x0000000000000000},
-		fe{0x0000000000000000, 0x0000000000000000, 0x0000000000000000, 0x0000000000000000},
+		fe{0xf91aba2654e8e3b1, 0x4771cb2fdc92ce12, 0xdcb16ae0fc8bdf35, 0x274aa195cd9d8be4},
+		fe{0x5cfc50ae18811f8b, 0x4bb28433cb43988c, 0x4fd35f13c3b56219, 0x301949bd2fc8883a},
 	},
 }
 
+// frobeniusCoeffs62[k] = nonResidue2^(2 * (p^k - 1) / 3)
 var frobeniusCoeffs62 = [6]fe2{
 	fe2{
 		fe{0xd35d438dc58f0d9d, 0x0a78eb28f5c70b3d, 0x666ea36f7879462c, 0x0e0a77c19a07df2f},
@@ -160,15 +211,16 @@ var frobeniusCoeffs62 = [6]fe2{
 		fe{0x2b19daf4bcc936d1, 0xa1a54e7a56f4299f, 0xb533eee05adeaef1, 0x170c812b84dda0b2},
 	},
 	fe2{
-		fe{0x0000000000000000, 0x0000000000000000, 0x0000000000000000, 0x0000000000000000},
+		fe{0x3350c88e13e80b9c, 0x7dce557cdb5e56b9, 0x6001b4b8b615564a, 0x2682e617020217e0},
 		fe{0x0000000000000000, 0x0000000000000000, 0x0000000000000000, 0x0000000000000000},
 	},
 	fe2{
-		fe{0x0000000000000000, 0x0000000000000000, 0x0000000000000000, 0x0000000000000000},
-		fe{0x0000000000000000, 0x0000000000000000, 0x0000000000000000, 0x0000000000000000},
+		fe{0x843420f1d8dadbd6, 0x31f010c9183fcdb2, 0x436330b527a76049, 0x13d47447f11adfe4},
+		fe{0xef494023a857fa74, 0x2a925d02d5ab101a, 0x83b015829ba62f10, 0x2539111d0c13aea3},
 	},
 }
 
+// frobeniusCoeffs12[k] = nonResidue2^((p^k - 1) / 6)
 var frobeniusCoeffs12 = [12]fe2{
 	fe2{
 		fe{0xd35d438dc58f0d9d, 0x0a78eb28f5c70b3d, 0x666ea36f7879462c, 0x0e0a77c19a07df2f},
@@ -187,35 +239,35 @@ var frobeniusCoeffs12 = [12]fe2{
 		fe{0xb1df4af7c39c1939, 0x3d9f02878a73bf7f, 0x9b2220928caf0ae0, 0x26684515eff054a6},
 	},
 	fe2{
-		fe{0x0000000000000000, 0x0000000000000000, 0x0000000000000000, 0x0000000000000000},
+		fe{0x3350c88e13e80b9c, 0x7dce557cdb5e56b9, 0x6001b4b8b615564a, 0x2682e617020217e0},
 		fe{0x0000000000000000, 0x0000000000000000, 0x0000000000000000, 0x0000000000000000},
 	},
 	fe2{
-		fe{0x0000000000000000, 0x0000000000000000, 0x0000000000000000, 0x0000000000000000},
-		fe{0x0000000000000000, 0x0000000000000000, 0x0000000000000000, 0x0000000000000000},
+		fe{0x86b76f821b329076, 0x408bf52b4d19b614, 0x53dfb9d0d985e92d, 0x051e20146982d2a7},
+		fe{0x0fbc9cd47752ebc7, 0x6d8fffe33415de24, 0xbef22cf038cf41b9, 0x15c0edff3c66bf54},
 	},
 	fe2{
 		fe{0x68c3488912edefaa, 0x8d087f6872aabf4f, 0x51e1a24709081231, 0x2259d6b14729c0fa},
 		fe{0x0000000000000000, 0x0000000000000000, 0x0000000000000000, 0x0000000000000000},
 	},
 	fe2{
-		fe{0x0000000000000000, 0x0000000000000000, 0x0000000000000000, 0x0000000000000000},
-		fe{0x0000000000000000, 0x0000000000000000, 0x0000000000000000, 0x0000000000000000},
+		fe{0x8c84e580a568b440, 0xcd164d1de0c21302, 0xa692585790f737d5, 0x2d7100fdc71265ad},
+		fe{0x99fdddf38c33cfd5, 0xc77267ed1213e931, 0xdc2052142da18f36, 0x1fbcf75c2da80ad7},
 	},
 	fe2{
-		fe{0x0000000000000000, 0x0000000000000000, 0x0000000000000000, 0x0000000000000000},
+		fe{0x71930c11d782e155, 0xa6bb947cffbe3323, 0xaa303344d4741444, 0x2c3b3f0d26594943},
 		fe{0x0000000000000000, 0x0000000000000000, 0x0000000000000000, 0x0000000000000000},
 	},
 	fe2{
-		fe{0x0000000000000000, 0x0000000000000000, 0x0000000000000000, 0x0000000000000000},
-		fe{0x0000000000000000, 0x0000000000000000, 0x0000000000000000, 0x0000000000000000},
+		fe{0x05cd75fe8a3623ca, 0x8c8a57f293a85cee, 0x52b29e86b7714ea8, 0x2852e0e95d8f9306},
+		fe{0x8a41411f14e0e40e, 0x59e26809ddfe0b0d, 0x1d2e2523f4d24d7d, 0x09fc095cf1414b83},
 	},
 	fe2{
-		fe{0x0000000000000000, 0x0000000000000000, 0x0000000000000000, 0x0000000000000000},
+		fe{0x08cfc388c494f1ab, 0x19b315148d1373d4, 0x584e90fdcb6c0213, 0x09e1685bdf2f8849},
 		fe{0x0000000000000000, 0x0000000000000000, 0x0000000000000000, 0x0000000000000000},
 	},
 	fe2{
-		fe{0x0000000000000000, 0x0000000000000000, 0x0000000000000000, 0x0000000000000000},
-		fe{0x0000000000000000, 0x0000000000000000, 0x0000000000000000, 0x0000000000000000},
+		fe{0xb5691c94bd4a6cd1, 0x56f575661b581478, 0x64708be5a7fb6f30, 0x2b462e5e77aecd82},
+		fe{0x2c63ef42612a1180, 0x29f16aae345bec69, 0xf95e18c648b216a4, 0x1aa36073a4cae0d4},
 	},
 }
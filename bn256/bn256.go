@@ -0,0 +1,342 @@
+// Package bn256 provides the API of go-ethereum crypto/bn256 on top of bn254 package,
+// so that callers written against go-ethereum types can switch backends.
+//
+// G1 and G2 points are marshaled as 32 byte big endian words, point at infinity is (0, 0)
+// and G2 coordinates are ordered imaginary part first. GT elements are marshaled
+// in 384 bytes with the layout of the cloudflare implementation, coefficients
+// of the highest degree come first at every tower level.
+package bn256
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"io"
+	"math/big"
+
+	"github.com/kilic/bn254"
+)
+
+const numBytes = 256 / 8
+
+// Order is the number of elements in both G1 and G2.
+var Order = new(big.Int).Set(bn254.Order)
+
+// P is a prime over which we form a basic field.
+var P, _ = new(big.Int).SetString("21888242871839275222246405745257275088696311157297823662689037894645226208583", 10)
+
+var (
+	errNotEnoughData      = errors.New("bn256: not enough data")
+	errCoordinateOverflow = errors.New("bn256: coordinate exceeds modulus")
+	errMalformedPoint     = errors.New("bn256: malformed point")
+)
+
+func randomK(r io.Reader) (k *big.Int, err error) {
+	for {
+		k, err = rand.Int(r, Order)
+		if err != nil || k.Sign() > 0 {
+			return
+		}
+	}
+}
+
+// checkCoordinates returns error if any of 32 byte words in given input is not less than modulus.
+func checkCoordinates(in []byte) error {
+	for i := 0; i < len(in); i += numBytes {
+		if new(big.Int).SetBytes(in[i:i+numBytes]).Cmp(P) != -1 {
+			return errCoordinateOverflow
+		}
+	}
+	return nil
+}
+
+// G1 is an abstract cyclic group. The zero value is suitable for use as the
+// output of an operation, but cannot be used as an input.
+type G1 struct {
+	p *bn254.PointG1
+}
+
+// RandomG1 returns x and g₁ˣ where x is a random, non-zero number read from r.
+func RandomG1(r io.Reader) (*big.Int, *G1, error) {
+	k, err := randomK(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	return k, new(G1).ScalarBaseMult(k), nil
+}
+
+func (e *G1) String() string {
+	return "bn256.G1" + pointString(bn254.NewG1().ToBytes(new(bn254.PointG1).Set(e.p)))
+}
+
+func (e *G1) init() {
+	if e.p == nil {
+		e.p = new(bn254.PointG1).Zero()
+	}
+}
+
+// ScalarBaseMult sets e to g*k where g is the generator of the group and then returns e.
+func (e *G1) ScalarBaseMult(k *big.Int) *G1 {
+	e.init()
+	g := bn254.NewG1()
+	g.MulScalar(e.p, g.One(), k)
+	return e
+}
+
+// ScalarMult sets e to a*k and then returns e.
+func (e *G1) ScalarMult(a *G1, k *big.Int) *G1 {
+	e.init()
+	bn254.NewG1().MulScalar(e.p, a.p, k)
+	return e
+}
+
+// Add sets e to a+b and then returns e.
+func (e *G1) Add(a, b *G1) *G1 {
+	e.init()
+	bn254.NewG1().Add(e.p, a.p, b.p)
+	return e
+}
+
+// Neg sets e to -a and then returns e.
+func (e *G1) Neg(a *G1) *G1 {
+	e.init()
+	bn254.NewG1().Neg(e.p, a.p)
+	return e
+}
+
+// Set sets e to a and then returns e.
+func (e *G1) Set(a *G1) *G1 {
+	e.init()
+	e.p.Set(a.p)
+	return e
+}
+
+// Marshal converts e to a byte slice.
+func (e *G1) Marshal() []byte {
+	e.init()
+	return bn254.NewG1().ToBytes(e.p)
+}
+
+// Unmarshal sets e to the result of converting the output of Marshal back into
+// a group element and then returns e.
+func (e *G1) Unmarshal(m []byte) ([]byte, error) {
+	if len(m) < 2*numBytes {
+		return nil, errNotEnoughData
+	}
+	if err := checkCoordinates(m[:2*numBytes]); err != nil {
+		return nil, err
+	}
+	p, err := bn254.NewG1().FromBytes(m[:2*numBytes])
+	if err != nil {
+		return nil, errMalformedPoint
+	}
+	e.p = p
+	return m[2*numBytes:], nil
+}
+
+// G2 is an abstract cyclic group. The zero value is suitable for use as the
+// output of an operation, but cannot be used as an input.
+type G2 struct {
+	p *bn254.PointG2
+}
+
+// RandomG2 returns x and g₂ˣ where x is a random, non-zero number read from r.
+func RandomG2(r io.Reader) (*big.Int, *G2, error) {
+	k, err := randomK(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	return k, new(G2).ScalarBaseMult(k), nil
+}
+
+func (e *G2) String() string {
+	return "bn256.G2" + pointString(bn254.NewG2().ToBytes(new(bn254.PointG2).Set(e.p)))
+}
+
+func (e *G2) init() {
+	if e.p == nil {
+		e.p = new(bn254.PointG2).Zero()
+	}
+}
+
+// ScalarBaseMult sets e to g*k where g is the generator of the group and then returns e.
+func (e *G2) ScalarBaseMult(k *big.Int) *G2 {
+	e.init()
+	g := bn254.NewG2()
+	g.MulScalar(e.p, g.One(), k)
+	return e
+}
+
+// ScalarMult sets e to a*k and then returns e.
+func (e *G2) ScalarMult(a *G2, k *big.Int) *G2 {
+	e.init()
+	bn254.NewG2().MulScalar(e.p, a.p, k)
+	return e
+}
+
+// Add sets e to a+b and then returns e.
+func (e *G2) Add(a, b *G2) *G2 {
+	e.init()
+	bn254.NewG2().Add(e.p, a.p, b.p)
+	return e
+}
+
+// Neg sets e to -a and then returns e.
+func (e *G2) Neg(a *G2) *G2 {
+	e.init()
+	bn254.NewG2().Neg(e.p, a.p)
+	return e
+}
+
+// Set sets e to a and then returns e.
+func (e *G2) Set(a *G2) *G2 {
+	e.init()
+	e.p.Set(a.p)
+	return e
+}
+
+// Marshal converts e into a byte slice.
+func (e *G2) Marshal() []byte {
+	e.init()
+	return bn254.NewG2().ToBytes(e.p)
+}
+
+// Unmarshal sets e to the result of converting the output of Marshal back into
+// a group element and then returns e. Points out of the correct subgroup are rejected.
+func (e *G2) Unmarshal(m []byte) ([]byte, error) {
+	if len(m) < 4*numBytes {
+		return nil, errNotEnoughData
+	}
+	if err := checkCoordinates(m[:4*numBytes]); err != nil {
+		return nil, err
+	}
+	g := bn254.NewG2()
+	p, err := g.FromBytes(m[:4*numBytes])
+	if err != nil {
+		return nil, errMalformedPoint
+	}
+	if !g.InCorrectSubgroup(p) {
+		return nil, errMalformedPoint
+	}
+	e.p = p
+	return m[4*numBytes:], nil
+}
+
+// GT is an abstract cyclic group. The zero value is suitable for use as the
+// output of an operation, but cannot be used as an input.
+type GT struct {
+	p *bn254.E
+}
+
+// Pair calculates an Optimal Ate pairing.
+func Pair(g1 *G1, g2 *G2) *GT {
+	return &GT{bn254.NewEngine().AddPair(g1.p, g2.p).Result()}
+}
+
+// PairingCheck calculates the Optimal Ate pairing for a set of points.
+func PairingCheck(a []*G1, b []*G2) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	e := bn254.NewEngine()
+	for i := 0; i < len(a); i++ {
+		e.AddPair(a[i].p, b[i].p)
+	}
+	return e.Check()
+}
+
+// Miller applies Miller's algorithm, which is a bilinear function from the
+// source groups to F_p^12. Miller(g1, g2).Finalize() is equivalent to Pair(g1,
+// g2).
+func Miller(g1 *G1, g2 *G2) *GT {
+	f, _ := bn254.NewEngine().MillerLoop([]*bn254.PointG1{g1.p}, []*bn254.PointG2{g2.p})
+	return &GT{f}
+}
+
+func (e *GT) String() string {
+	return "bn256.GT(" + hex.EncodeToString(e.Marshal()) + ")"
+}
+
+func (e *GT) init() {
+	if e.p == nil {
+		e.p = bn254.NewGT().New()
+	}
+}
+
+// ScalarMult sets e to a*k and then returns e.
+func (e *GT) ScalarMult(a *GT, k *big.Int) *GT {
+	e.init()
+	gt := bn254.NewGT()
+	// Miller loop outputs are not in cyclotomic subgroup so apply plain square and multiply
+	z := gt.New()
+	for i := k.BitLen() - 1; i >= 0; i-- {
+		gt.Mul(z, z, z)
+		if k.Bit(i) == 1 {
+			gt.Mul(z, z, a.p)
+		}
+	}
+	e.p.Set(z)
+	return e
+}
+
+// Add sets e to a+b and then returns e. Group operation of GT is multiplication in F_p^12.
+func (e *GT) Add(a, b *GT) *GT {
+	e.init()
+	bn254.NewGT().Mul(e.p, a.p, b.p)
+	return e
+}
+
+// Neg sets e to -a and then returns e. Negation in GT is inversion in F_p^12.
+func (e *GT) Neg(a *GT) *GT {
+	e.init()
+	bn254.NewGT().Inverse(e.p, a.p)
+	return e
+}
+
+// Set sets e to a and then returns e.
+func (e *GT) Set(a *GT) *GT {
+	e.init()
+	e.p.Set(a.p)
+	return e
+}
+
+// Finalize is a linear function from F_p^12 to GT.
+func (e *GT) Finalize() *GT {
+	e.init()
+	e.p = bn254.NewEngine().FinalExponentiation(e.p)
+	return e
+}
+
+// Marshal converts e into a byte slice.
+func (e *GT) Marshal() []byte {
+	e.init()
+	return bn254.NewGT().ToBytes(e.p)
+}
+
+// Unmarshal sets e to the result of converting the output of Marshal back into
+// a group element and then returns e.
+func (e *GT) Unmarshal(m []byte) ([]byte, error) {
+	if len(m) < 12*numBytes {
+		return nil, errNotEnoughData
+	}
+	if err := checkCoordinates(m[:12*numBytes]); err != nil {
+		return nil, err
+	}
+	p, err := bn254.NewGT().FromBytesUnchecked(m[:12*numBytes])
+	if err != nil {
+		return nil, err
+	}
+	e.p = p
+	return m[12*numBytes:], nil
+}
+
+func pointString(in []byte) string {
+	s := "("
+	for i := 0; i < len(in); i += numBytes {
+		if i > 0 {
+			s += ", "
+		}
+		s += new(big.Int).SetBytes(in[i : i+numBytes]).Text(16)
+	}
+	return s + ")"
+}
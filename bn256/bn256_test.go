@@ -0,0 +1,264 @@
+package bn256
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/kilic/bn254"
+)
+
+func TestG1Marshal(t *testing.T) {
+	_, Ga, err := RandomG1(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ma := Ga.Marshal()
+	Gb := new(G1)
+	if _, err := Gb.Unmarshal(ma); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(ma, Gb.Marshal()) {
+		t.Fatal("bytes are different")
+	}
+	// point at infinity
+	zero := new(G1).ScalarBaseMult(new(big.Int))
+	if !bytes.Equal(zero.Marshal(), make([]byte, 64)) {
+		t.Fatal("infinity should be marshaled as zeros")
+	}
+	if _, err := Gb.Unmarshal(make([]byte, 64)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Gb.Unmarshal(ma[:63]); err == nil {
+		t.Fatal("short input should be rejected")
+	}
+	malformed := append([]byte{}, ma...)
+	malformed[63] ^= 1
+	if _, err := Gb.Unmarshal(malformed); err == nil {
+		t.Fatal("point not on curve should be rejected")
+	}
+	overflow := append(P.Bytes(), ma[32:]...)
+	if _, err := Gb.Unmarshal(overflow); err == nil {
+		t.Fatal("coordinate larger than modulus should be rejected")
+	}
+}
+
+func TestG2Marshal(t *testing.T) {
+	_, Ga, err := RandomG2(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ma := Ga.Marshal()
+	Gb := new(G2)
+	rest, err := Gb.Unmarshal(append(ma, 1, 2, 3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(rest, []byte{1, 2, 3}) {
+		t.Fatal("bad remaining bytes")
+	}
+	if !bytes.Equal(ma, Gb.Marshal()) {
+		t.Fatal("bytes are different")
+	}
+	// on curve point which is not in the correct subgroup
+	g2 := bn254.NewG2()
+	in := make([]byte, 64)
+	_, _ = rand.Read(in)
+	p, err := g2.MapToPointTI(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !g2.InCorrectSubgroup(p) {
+		if _, err := Gb.Unmarshal(g2.ToBytes(p)); err == nil {
+			t.Fatal("point out of subgroup should be rejected")
+		}
+	}
+}
+
+func TestGTMarshal(t *testing.T) {
+	_, Ga, err := RandomG1(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, Gb, err := RandomG2(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := Pair(Ga, Gb)
+	m := e.Marshal()
+	if len(m) != 384 {
+		t.Fatal("bad GT encoding length")
+	}
+	e2 := new(GT)
+	if _, err := e2.Unmarshal(m); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(m, e2.Marshal()) {
+		t.Fatal("bytes are different")
+	}
+	// one is encoded as last word of the layout
+	one := new(GT).ScalarMult(e, new(big.Int)).Marshal()
+	expected := make([]byte, 384)
+	expected[383] = 1
+	if !bytes.Equal(one, expected) {
+		t.Fatal("bad encoding of one")
+	}
+}
+
+func TestBilinearity(t *testing.T) {
+	for i := 0; i < 2; i++ {
+		a, p1, _ := RandomG1(rand.Reader)
+		b, p2, _ := RandomG2(rand.Reader)
+		e1 := Pair(p1, p2)
+		e2 := Pair(new(G1).ScalarBaseMult(big.NewInt(1)), new(G2).ScalarBaseMult(big.NewInt(1)))
+		e2.ScalarMult(e2, a)
+		e2.ScalarMult(e2, b)
+		if !bytes.Equal(e1.Marshal(), e2.Marshal()) {
+			t.Fatal("bad pairing result")
+		}
+	}
+}
+
+func TestTripartiteDiffieHellman(t *testing.T) {
+	a, _ := rand.Int(rand.Reader, Order)
+	b, _ := rand.Int(rand.Reader, Order)
+	c, _ := rand.Int(rand.Reader, Order)
+	pa, pb, pc := new(G1), new(G1), new(G1)
+	qa, qb, qc := new(G2), new(G2), new(G2)
+	if _, err := pa.Unmarshal(new(G1).ScalarBaseMult(a).Marshal()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := qa.Unmarshal(new(G2).ScalarBaseMult(a).Marshal()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pb.Unmarshal(new(G1).ScalarBaseMult(b).Marshal()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := qb.Unmarshal(new(G2).ScalarBaseMult(b).Marshal()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pc.Unmarshal(new(G1).ScalarBaseMult(c).Marshal()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := qc.Unmarshal(new(G2).ScalarBaseMult(c).Marshal()); err != nil {
+		t.Fatal(err)
+	}
+	k1 := Pair(pb, qc)
+	k1.ScalarMult(k1, a)
+	k1Bytes := k1.Marshal()
+	k2 := Pair(pc, qa)
+	k2.ScalarMult(k2, b)
+	k2Bytes := k2.Marshal()
+	k3 := Pair(pa, qb)
+	k3.ScalarMult(k3, c)
+	k3Bytes := k3.Marshal()
+	if !bytes.Equal(k1Bytes, k2Bytes) || !bytes.Equal(k2Bytes, k3Bytes) {
+		t.Fatal("keys didn't agree")
+	}
+}
+
+func TestMillerFinalize(t *testing.T) {
+	_, p1, _ := RandomG1(rand.Reader)
+	_, p2, _ := RandomG2(rand.Reader)
+	e1 := Pair(p1, p2)
+	e2 := Miller(p1, p2).Finalize()
+	if !bytes.Equal(e1.Marshal(), e2.Marshal()) {
+		t.Fatal("bad miller loop and final exponentiation")
+	}
+	// e(p1, p2) * e(-p1, p2) == 1
+	e3 := new(GT).Add(e1, Pair(new(G1).Neg(p1), p2))
+	e4 := new(GT).Add(e1, new(GT).Neg(e1))
+	if !bytes.Equal(e3.Marshal(), e4.Marshal()) {
+		t.Fatal("bad GT group operation")
+	}
+}
+
+func TestPairingCheck(t *testing.T) {
+	a, p1, _ := RandomG1(rand.Reader)
+	_, p2, _ := RandomG2(rand.Reader)
+	// e(a * G1, p2) * e(-G1, a * p2) == 1
+	g1 := new(G1).ScalarBaseMult(big.NewInt(1))
+	q2 := new(G2).ScalarMult(p2, a)
+	if !PairingCheck([]*G1{p1, new(G1).Neg(g1)}, []*G2{p2, q2}) {
+		t.Fatal("pairing check should pass")
+	}
+	if PairingCheck([]*G1{p1, g1}, []*G2{p2, q2}) {
+		t.Fatal("pairing check should fail")
+	}
+	if PairingCheck([]*G1{p1}, []*G2{p2, q2}) {
+		t.Fatal("length mismatch should fail")
+	}
+}
+
+func TestG1Add(t *testing.T) {
+	a, p1, _ := RandomG1(rand.Reader)
+	b, p2, _ := RandomG1(rand.Reader)
+	sum := new(G1).Add(p1, p2)
+	expected := new(G1).ScalarBaseMult(new(big.Int).Add(a, b))
+	if !bytes.Equal(sum.Marshal(), expected.Marshal()) {
+		t.Fatal("bad g1 addition")
+	}
+	q2 := new(G2).ScalarBaseMult(a)
+	r2 := new(G2).Add(q2, new(G2).Neg(new(G2).Set(q2)))
+	if !bytes.Equal(r2.Marshal(), make([]byte, 128)) {
+		t.Fatal("bad g2 negation")
+	}
+}
+
+// known answers are produced by go-ethereum crypto/bn256/cloudflare
+var (
+	katScalarA, _ = new(big.Int).SetString("1f8c6a7b3c0d2e9f41a5b6c7d8e9f00112233445566778899aabbccddeeff001", 16)
+	katScalarB, _ = new(big.Int).SetString("2a1b3c4d5e6f708192a3b4c5d6e7f8091a2b3c4d5e6f708192a3b4c5d6e7f809", 16)
+)
+
+const (
+	katG1One   = "00000000000000000000000000000000000000000000000000000000000000010000000000000000000000000000000000000000000000000000000000000002"
+	katG1A     = "1acef395be718efd96f07f6f5177726c3236a48d2cf1a40338aca13d50c4d8262be8c98c3de21b6a0ac5512e84d854fb3ffdeb22ae218f862a82cccffc6a0a0e"
+	katG2One   = "198e9393920d483a7260bfb731fb5d25f1aa493335a9e71297e485b7aef312c21800deef121f1e76426a00665e5c4479674322d4f75edadd46debd5cd992f6ed090689d0585ff075ec9e99ad690c3395bc4b313370b38ef355acdadcd122975b12c85ea5db8c6deb4aab71808dcb408fe3d1e7690c43d37b4ce6cc0166fa7daa"
+	katG2B     = "27d9afe282aaf4dd9d29c5b7e1b882879b00ed1aa4f9f2f045943e59aa4e9cf3173dc3c60d8f4f6dcef1d030d99171db8a29d1ec9f36b8f16157a7fd5bdcaab52ba5076a7a73a6141c4fe8d45d28f6df3054fde2f585a06c9c80c76f27ac1b5011bcfd24afb2677a725a4a89731eddd3a47a8b239126ca05a6eeeb314248215c"
+	katPairOne = "108c19d15f9446f744d0f110405d3856d6cc3bda6c4d537663729f52576284170dc26f240656bbe2029bd441d77c221f0ba4c70c94b29b5f17f0f6d08745a069279db296f9d479292532c7c493d8e0722b6efae42158387564889c79fc038ee31ad9db1937fd72f4ac462173d31d3d6117411fa48dba8d499d762b47edb3b54a27ed208e7a0b55ae6e710bbfbd2fd922669c026360e37cc5b2ab8624115361042c53748bcd21a7c038fb30ddc8ac3bf0af25d7859cfbc12c30c866276c5659092b03614464f04dd772d86df88674c270ffc8747ea13e72da95e3594468f222c401676555de427abc409c4a394bc5426886302996919d4bf4bdd02236e14b36362067586885c3318eeffa1938c754fe3c60224ee5ae15e66af6b5104c47c8c5d80e841c2ac18a4003ac9326b9558380e0bc27fdd375e3605f96b819a358d34bde084f330485b09e866bc2f2ea2b897394deaf3f12aa31f28cb0552990967d470412c70e90e12b7874510cd1707e8856f71bf7f61d72631e268fca81000db9a1f5"
+	katPairAB  = "2d0705e6fad614da10713ff33410aeb260d4fb0eb891deaa796b84b88e52b72925c95406932a371b26a359954cc856a90a9bac925e62490a65c9ac150eca0e6c066339dd1137768c58ee790b04f1333e2466c83e7a045e170fccd7847f7dd41e28933e52cfe92662b0dec71aa0b08be162c2552868769be8bec47c8745b27b8e07a1ce63b5291bc06914e1dab528725a88a2983cf053838aae8e992a0d7b45ba141d9397381fdfd8355df8ff37e8b191467172051262e7141a89fd40c55df20c2cd94c8d54070769b33a5af74617bb8583bd90adfdbbe88023d4278fae73edcb1ca811c96c0c34cbb81005b6b9450d79805382666eca7fce746d57f898f300001b1a0426455c6f705469f50020e8c2e46be629eccf976774f54af78330201eee0bd9b196a7b569f8f06fe731536bc0fb7a7ec66552619bfba4e87c65cab9bde7122d47bb71c11ea0806fa97cb746828b43c523ac2551cddd5ba616a0ead78e461d7a247e020780d7aabee1a067287418eab0becbea309bf64beacbc387e7ef1a"
+	katGTExpA  = "1c10bddcbf31052abe61ad963f6ea8d1f712edd6edfe397cd8bb77c012f8bc4a2c4b26e92f35cf068ae936e46202470ce1684b9e853a8cb4373901f3b18c96d42defbc5b78e4943ceed29cc8eebbafaae16d474e7d852a7b7c43e5681f3417940274c40990e9d95ee3db34b8556ce46764200257645f12bc41f2315788bce16618093eba37425cff4434a57423c6e9a215d87f2bc870bf46c4aaaaed1a8750bd2adb6f26afa097f755237e8a13e1d18b628d29b47eb745cbe4603c2eb54d15550f7f2a392876f7365e86f2c43ea0a92129b2f46a55e28a210028430830759978261479b5cd498fa93bc2aa3e83e48506a530441e909ee7387059e28b7d1a3f9a10a61d264c19795c97cd4657eaefc0a897d99b67bdc70b4784cabf79ca5c5db023f0e82b9cab42bd860a1acbfc635d69dc24dea836aa6f5ebe1acfcb175e194929f9c439989f19189ac5c56b80b331a47ecd8d65def70601818d0e575bb8f6a317d4d7a38988353fca0536fa4318c29e670149f8b4211cb60663900e0fa191c1"
+)
+
+func checkKnownAnswer(t *testing.T, name string, out []byte, expected string) {
+	if hex.EncodeToString(out) != expected {
+		t.Fatalf("%s: bad result\n%x\nexpected\n%s", name, out, expected)
+	}
+}
+
+func TestKnownAnswers(t *testing.T) {
+	one := big.NewInt(1)
+	g1One := new(G1).ScalarBaseMult(one)
+	g1A := new(G1).ScalarBaseMult(katScalarA)
+	g2One := new(G2).ScalarBaseMult(one)
+	g2B := new(G2).ScalarBaseMult(katScalarB)
+	checkKnownAnswer(t, "g1 generator", g1One.Marshal(), katG1One)
+	checkKnownAnswer(t, "g1 multiple", g1A.Marshal(), katG1A)
+	checkKnownAnswer(t, "g2 generator", g2One.Marshal(), katG2One)
+	checkKnownAnswer(t, "g2 multiple", g2B.Marshal(), katG2B)
+	pairOne := Pair(g1One, g2One)
+	checkKnownAnswer(t, "pairing of generators", pairOne.Marshal(), katPairOne)
+	checkKnownAnswer(t, "pairing of multiples", Pair(g1A, g2B).Marshal(), katPairAB)
+	checkKnownAnswer(t, "gt exponentiation", new(GT).ScalarMult(pairOne, katScalarA).Marshal(), katGTExpA)
+	// known answers are unmarshaled back into same bytes
+	for _, v := range []struct {
+		expected string
+		e        interface {
+			Marshal() []byte
+			Unmarshal([]byte) ([]byte, error)
+		}
+	}{
+		{katG1A, new(G1)},
+		{katG2B, new(G2)},
+		{katPairAB, new(GT)},
+	} {
+		in, _ := hex.DecodeString(v.expected)
+		if _, err := v.e.Unmarshal(in); err != nil {
+			t.Fatal(err)
+		}
+		checkKnownAnswer(t, "unmarshal", v.e.Marshal(), v.expected)
+	}
+}
@@ -0,0 +1,164 @@
+package bn254
+
+import (
+	"crypto/rand"
+	"errors"
+	"io"
+	"math/big"
+)
+
+// twoTo256 is the exclusive upper bound of 32 byte integers.
+var twoTo256 = new(big.Int).Lsh(big.NewInt(1), 256)
+
+// EncodeUniform encodes a G1 point into 64 bytes that are indistinguishable from uniform random bytes.
+// Encoding is Elligator Squared https://eprint.iacr.org/2014/043.pdf built on top of Fouque Tibouchi map.
+// Output is concatenation of two 32 byte big endian integers u and v where p = f(u mod p) + f(v mod p).
+// u is sampled uniformly and v is sampled uniformly among all 32 byte representatives
+// of all preimages of p - f(u), so that the pair is uniform over all encodings of p.
+func (g *G1) EncodeUniform(p *PointG1, r io.Reader) ([]byte, error) {
+	target := new(PointG1).Set(p)
+	q := &PointG1{}
+	max := big.NewInt(4 * 6)
+	for {
+		uBytes := make([]byte, 32)
+		if _, err := io.ReadFull(r, uBytes); err != nil {
+			return nil, err
+		}
+		u := feFromBytesReduced(uBytes)
+		fu, err := g.mapToPointFT(u)
+		if err != nil {
+			return nil, err
+		}
+		g.Sub(q, target, fu)
+		if g.IsZero(q) {
+			continue
+		}
+		preimages := g.mapToPointFTPreimages(q)
+		// a point has at most 4 preimages and a field element has at most 6 representatives
+		// so pick one of 4 * 6 slots and reject if it is empty
+		k, err := rand.Int(r, max)
+		if err != nil {
+			return nil, err
+		}
+		i, j := int(k.Int64()/6), k.Int64()%6
+		if i >= len(preimages) {
+			continue
+		}
+		v := toBig(preimages[i])
+		v.Add(v, new(big.Int).Mul(big.NewInt(j), pbig))
+		if v.Cmp(twoTo256) != -1 {
+			continue
+		}
+		out := make([]byte, 64)
+		copy(out[:32], uBytes)
+		vBytes := v.Bytes()
+		copy(out[64-len(vBytes):], vBytes)
+		return out, nil
+	}
+}
+
+// DecodeUniform decodes 64 bytes generated by EncodeUniform into a G1 point.
+// Any 64 byte input decodes to a valid point.
+func (g *G1) DecodeUniform(in []byte) (*PointG1, error) {
+	if len(in) != 64 {
+		return nil, errors.New("input string should be equal 64 bytes")
+	}
+	p0, err := g.mapToPointFT(feFromBytesReduced(in[:32]))
+	if err != nil {
+		return nil, err
+	}
+	p1, err := g.mapToPointFT(feFromBytesReduced(in[32:]))
+	if err != nil {
+		return nil, err
+	}
+	g.Add(p0, p0, p1)
+	return g.Affine(p0), nil
+}
+
+// mapToPointFTPreimages returns all field elements t such that mapToPointFT(t) is equal to given point.
+func (g *G1) mapToPointFTPreimages(p *PointG1) []*fe {
+	if g.IsZero(p) {
+		return nil
+	}
+	p = g.Affine(new(PointG1).Set(p))
+	x := &p[0]
+	t := g.t
+	// d = 1 + b
+	d := new(fe)
+	add(d, b, one)
+	var candidates []*fe
+
+	// x = x1(t) or x = x2(t) where
+	// x1 = zz - sqrt(-3) * t^2 / (d + t^2) and x2 = -1 - x1
+	// so that t^2 = d * (zz - x1) / (sqrt(-3) - zz + x1)
+	x1 := [2]fe{}
+	x1[0].set(x)
+	sub(&x1[1], negativeOne, x)
+	for i := 0; i < 2; i++ {
+		sub(t[0], sqrtMinus3, zz)
+		add(t[0], t[0], &x1[i])
+		if t[0].isZero() {
+			continue
+		}
+		inverse(t[0], t[0])
+		s := new(fe)
+		sub(s, zz, &x1[i])
+		mul(s, s, d)
+		mul(s, s, t[0])
+		candidates = append(candidates, s)
+	}
+
+	// x = x3(t) = 1 + 1 / w^2 where w = sqrt(-3) * t / (d + t^2)
+	// so that t^2 is a root of s^2 + (2 * d + 3 * (x - 1)) * s + d^2
+	sub(t[0], x, one)
+	double(t[1], t[0])
+	add(t[0], t[0], t[1])
+	double(t[1], d)
+	add(t[0], t[0], t[1])
+	square(t[1], t[0])
+	square(t[2], d)
+	double(t[2], t[2])
+	double(t[2], t[2])
+	sub(t[1], t[1], t[2])
+	if sqrt(t[2], t[1]) {
+		s0, s1 := new(fe), new(fe)
+		sub(s0, t[2], t[0])
+		mul(s0, s0, twoInv)
+		neg(t[2], t[2])
+		sub(s1, t[2], t[0])
+		mul(s1, s1, twoInv)
+		candidates = append(candidates, s0, s1)
+	}
+
+	var preimages []*fe
+	for _, s := range candidates {
+		u := [2]fe{}
+		if !sqrt(&u[0], s) {
+			continue
+		}
+		neg(&u[1], &u[0])
+		for i := 0; i < 2; i++ {
+			found := false
+			for _, t := range preimages {
+				found = found || t.equal(&u[i])
+			}
+			if found {
+				continue
+			}
+			q, err := g.mapToPointFT(&u[i])
+			if err != nil || !g.Equal(q, p) {
+				continue
+			}
+			preimages = append(preimages, new(fe).set(&u[i]))
+		}
+	}
+	return preimages
+}
+
+// feFromBytesReduced interprets 32 bytes as big endian integer and reduces it by the modulus.
+func feFromBytesReduced(in []byte) *fe {
+	e := new(big.Int).SetBytes(in)
+	e.Mod(e, pbig)
+	fe, _ := fromBig(e)
+	return fe
+}
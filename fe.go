@@ -16,6 +16,15 @@ type fe6 /**			***/ [3]fe2
 
 type fe12 /**			***/ [2]fe6
 
+// wfe is a double width integer that holds unreduced products of field elements.
+// Wide values are kept in [0, p * 2^256) so that a single Montgomery reduction
+// brings them back to a field element.
+type wfe /***			***/ [8]uint64
+
+type wfe2 /**			***/ [2]wfe
+
+type wfe6 /**			***/ [3]wfe2
+
 func (fe *fe) setBytes(in []byte) *fe {
 	size := 32
 	l := len(in)
@@ -143,6 +152,30 @@ func (fe *fe) equal(fe2 *fe) bool {
 	return fe2[0] == fe[0] && fe2[1] == fe[1] && fe2[2] == fe[2] && fe2[3] == fe[3]
 }
 
+// isZeroMask returns all ones if element is zero and zero otherwise in constant time.
+func (fe *fe) isZeroMask() uint64 {
+	t := fe[0] | fe[1] | fe[2] | fe[3]
+	return ((t | -t) >> 63) - 1
+}
+
+// equalMask returns all ones if elements are equal and zero otherwise in constant time.
+func (fe *fe) equalMask(fe2 *fe) uint64 {
+	t := (fe[0] ^ fe2[0]) | (fe[1] ^ fe2[1]) | (fe[2] ^ fe2[2]) | (fe[3] ^ fe2[3])
+	return ((t | -t) >> 63) - 1
+}
+
+// cmov assigns fe2 to fe if cond is one and leaves fe unchanged if cond is zero.
+// A mask returned by equalMask or isZeroMask is also accepted as cond.
+// Selection is done in constant time.
+func (fe *fe) cmov(fe2 *fe, cond uint64) *fe {
+	mask := -(cond & 1)
+	fe[0] ^= (fe[0] ^ fe2[0]) & mask
+	fe[1] ^= (fe[1] ^ fe2[1]) & mask
+	fe[2] ^= (fe[2] ^ fe2[2]) & mask
+	fe[3] ^= (fe[3] ^ fe2[3]) & mask
+	return fe
+}
+
 func (e *fe) signBE() bool {
 	negZ, z := new(fe), new(fe)
 	fromMont(z, e)
@@ -156,6 +189,14 @@ func (e *fe) sign() bool {
 	return r[0]&1 == 0
 }
 
+// sgn0 returns the parity of the element as defined in the hash to curve specification.
+// It is computed in constant time.
+func (e *fe) sgn0() uint64 {
+	r := new(fe)
+	fromMont(r, e)
+	return r[0] & 1
+}
+
 func (fe *fe) div2(e uint64) {
 	fe[0] = fe[0]>>1 | fe[1]<<63
 	fe[1] = fe[1]>>1 | fe[2]<<63
@@ -214,6 +255,24 @@ func (e *fe2) equal(e2 *fe2) bool {
 	return e[0].equal(&e2[0]) && e[1].equal(&e2[1])
 }
 
+// isZeroMask returns all ones if element is zero and zero otherwise in constant time.
+func (e *fe2) isZeroMask() uint64 {
+	return e[0].isZeroMask() & e[1].isZeroMask()
+}
+
+// equalMask returns all ones if elements are equal and zero otherwise in constant time.
+func (e *fe2) equalMask(e2 *fe2) uint64 {
+	return e[0].equalMask(&e2[0]) & e[1].equalMask(&e2[1])
+}
+
+// cmov assigns e2 to e if cond is one and leaves e unchanged if cond is zero.
+// Selection is done in constant time.
+func (e *fe2) cmov(e2 *fe2, cond uint64) *fe2 {
+	e[0].cmov(&e2[0], cond)
+	e[1].cmov(&e2[1], cond)
+	return e
+}
+
 func (e *fe2) signBE() bool {
 	if !e[1].isZero() {
 		return e[1].signBE()
@@ -231,6 +290,13 @@ func (e *fe2) sign() bool {
 	return r[0]&1 == 0
 }
 
+// sgn0 returns the sign of the element as defined in the hash to curve specification, that
+// is the parity of the first coefficient or of the second one if the first is zero.
+// It is computed in constant time.
+func (e *fe2) sgn0() uint64 {
+	return e[0].sgn0() | (e[0].isZeroMask() & e[1].sgn0())
+}
+
 func (e *fe6) zero() *fe6 {
 	e[0].zero()
 	e[1].zero()
@@ -280,6 +346,15 @@ func (e *fe6) equal(e2 *fe6) bool {
 	return e[0].equal(&e2[0]) && e[1].equal(&e2[1]) && e[2].equal(&e2[2])
 }
 
+// cmov assigns e2 to e if cond is one and leaves e unchanged if cond is zero.
+// Selection is done in constant time.
+func (e *fe6) cmov(e2 *fe6, cond uint64) *fe6 {
+	e[0].cmov(&e2[0], cond)
+	e[1].cmov(&e2[1], cond)
+	e[2].cmov(&e2[2], cond)
+	return e
+}
+
 func (e *fe12) zero() *fe12 {
 	e[0].zero()
 	e[1].zero()
@@ -321,3 +396,11 @@ func (e *fe12) isZero() bool {
 func (e *fe12) equal(e2 *fe12) bool {
 	return e[0].equal(&e2[0]) && e[1].equal(&e2[1])
 }
+
+// cmov assigns e2 to e if cond is one and leaves e unchanged if cond is zero.
+// Selection is done in constant time.
+func (e *fe12) cmov(e2 *fe12, cond uint64) *fe12 {
+	e[0].cmov(&e2[0], cond)
+	e[1].cmov(&e2[1], cond)
+	return e
+}
@@ -0,0 +1,86 @@
+// Package fft implements radix-2 number theoretic transforms over the scalar field of BN254.
+package fft
+
+import (
+	"errors"
+	"math/big"
+	"math/bits"
+
+	"github.com/kilic/bn254"
+)
+
+type Fr = bn254.Fr
+
+// MaxLogSize is the 2-adicity of the scalar field. Largest domain has 2^MaxLogSize elements.
+const MaxLogSize = 28
+
+// rootOfUnity generates the subgroup of order 2^28, it is equal to 5^((q - 1) / 2^28)
+var rootOfUnity, _ = new(big.Int).SetString("2a3c09f0a58a7e8500e0a7eb8ef62abc402d111e41112ed49bd61b6e725b19f0", 16)
+
+// multiplicativeGenerator generates the multiplicative group of the scalar field and is used as
+// the coset shift
+var multiplicativeGenerator = big.NewInt(5)
+
+// Domain is a multiplicative subgroup of the scalar field of power of two size.
+type Domain struct {
+	Size    int
+	LogSize uint
+	// Generator is a primitive root of unity of order Size.
+	Generator    Fr
+	GeneratorInv Fr
+	SizeInv      Fr
+	// CosetShift is the multiplicative generator of the field, cosets are in form of CosetShift * H.
+	CosetShift    Fr
+	CosetShiftInv Fr
+	// twiddles are the first Size / 2 powers of generator
+	twiddles []Fr
+}
+
+// NewDomain returns an evaluation domain with given size that should be a power of two not
+// larger than 2^28.
+func NewDomain(size int) (*Domain, error) {
+	if size <= 0 || size&(size-1) != 0 {
+		return nil, errors.New("domain size should be a power of two")
+	}
+	logSize := uint(bits.TrailingZeros64(uint64(size)))
+	if logSize > MaxLogSize {
+		return nil, errors.New("domain size is larger than 2^28")
+	}
+	d := &Domain{Size: size, LogSize: logSize}
+	d.Generator.SetBig(rootOfUnity)
+	for i := logSize; i < MaxLogSize; i++ {
+		d.Generator.Square(&d.Generator)
+	}
+	d.GeneratorInv.Inverse(&d.Generator)
+	d.SizeInv.SetUint64(uint64(size))
+	d.SizeInv.Inverse(&d.SizeInv)
+	d.CosetShift.SetBig(multiplicativeGenerator)
+	d.CosetShiftInv.Inverse(&d.CosetShift)
+	d.twiddles = powers(&d.Generator, size/2)
+	return d, nil
+}
+
+// Element returns the i-th element of the domain which is Generator^i.
+func (d *Domain) Element(i int) *Fr {
+	i &= d.Size - 1
+	if d.Size == 1 {
+		return new(Fr).One()
+	}
+	if i < d.Size/2 {
+		return new(Fr).Set(&d.twiddles[i])
+	}
+	return new(Fr).Neg(&d.twiddles[i-d.Size/2])
+}
+
+// powers returns [1, a, a^2, ..., a^(n-1)].
+func powers(a *Fr, n int) []Fr {
+	p := make([]Fr, n)
+	if n == 0 {
+		return p
+	}
+	p[0].One()
+	for i := 1; i < n; i++ {
+		p[i].Mul(&p[i-1], a)
+	}
+	return p
+}
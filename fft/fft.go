@@ -0,0 +1,149 @@
+package fft
+
+import (
+	"errors"
+	"math/bits"
+	"runtime"
+	"sync"
+)
+
+// parallelThreshold is the smallest sub transform size that is split across goroutines.
+const parallelThreshold = 1 << 10
+
+// FFT evaluates the polynomial with coefficients a over the domain in place. Both input and
+// output are in natural order.
+func (d *Domain) FFT(a []Fr) error {
+	if len(a) != d.Size {
+		return errors.New("input length should be equal to domain size")
+	}
+	dif(a, d.twiddles, 1, parallelDepth())
+	BitReverse(a)
+	return nil
+}
+
+// InverseFFT interpolates the evaluations a over the domain into coefficients in place. Both
+// input and output are in natural order.
+func (d *Domain) InverseFFT(a []Fr) error {
+	if err := d.FFT(a); err != nil {
+		return err
+	}
+	// transform with the inverse generator is the forward transform with indexes negated
+	for i, j := 1, len(a)-1; i < j; i, j = i+1, j-1 {
+		a[i], a[j] = a[j], a[i]
+	}
+	scale(a, &d.SizeInv)
+	return nil
+}
+
+// CosetFFT evaluates the polynomial with coefficients a over the coset CosetShift * H in
+// place.
+func (d *Domain) CosetFFT(a []Fr) error {
+	if len(a) != d.Size {
+		return errors.New("input length should be equal to domain size")
+	}
+	shift(a, &d.CosetShift)
+	return d.FFT(a)
+}
+
+// InverseCosetFFT interpolates the evaluations a over the coset CosetShift * H into
+// coefficients in place.
+func (d *Domain) InverseCosetFFT(a []Fr) error {
+	if err := d.InverseFFT(a); err != nil {
+		return err
+	}
+	shift(a, &d.CosetShiftInv)
+	return nil
+}
+
+// BitReverse permutes a in place so that element at index i moves to the index with
+// reversed bits of i. Length of a should be a power of two.
+func BitReverse(a []Fr) {
+	n := uint64(len(a))
+	if n < 2 {
+		return
+	}
+	shift := 64 - uint(bits.TrailingZeros64(n))
+	for i := uint64(0); i < n; i++ {
+		j := bits.Reverse64(i) >> shift
+		if i < j {
+			a[i], a[j] = a[j], a[i]
+		}
+	}
+}
+
+// dif is the decimation in frequency transform which takes input in natural order and leaves
+// output in bit reversed order. Twiddle factor of the j-th butterfly is twiddles[j * stride].
+// Until depth reaches zero butterflies of a layer and the two halves are processed
+// concurrently.
+func dif(a []Fr, twiddles []Fr, stride int, depth int) {
+	n := len(a)
+	if n < 2 {
+		return
+	}
+	m := n / 2
+	if depth > 0 && m >= parallelThreshold {
+		var wg sync.WaitGroup
+		chunk := (m + runtime.NumCPU() - 1) / runtime.NumCPU()
+		for start := 0; start < m; start += chunk {
+			end := start + chunk
+			if end > m {
+				end = m
+			}
+			wg.Add(1)
+			go func(start, end int) {
+				butterflies(a, twiddles, stride, start, end)
+				wg.Done()
+			}(start, end)
+		}
+		wg.Wait()
+		wg.Add(2)
+		go func() {
+			dif(a[:m], twiddles, stride*2, depth-1)
+			wg.Done()
+		}()
+		go func() {
+			dif(a[m:], twiddles, stride*2, depth-1)
+			wg.Done()
+		}()
+		wg.Wait()
+		return
+	}
+	butterflies(a, twiddles, stride, 0, m)
+	dif(a[:m], twiddles, stride*2, 0)
+	dif(a[m:], twiddles, stride*2, 0)
+}
+
+// butterflies applies (u, v) -> (u + v, (u - v) * w) to pairs j and j + n / 2 for j in
+// [start, end).
+func butterflies(a []Fr, twiddles []Fr, stride int, start, end int) {
+	m := len(a) / 2
+	t := new(Fr)
+	for j := start; j < end; j++ {
+		u, v := &a[j], &a[j+m]
+		t.Sub(u, v)
+		u.Add(u, v)
+		v.Mul(t, &twiddles[j*stride])
+	}
+}
+
+// parallelDepth returns number of recursion levels in which work is split so that there
+// are at least as many concurrent tasks as CPUs.
+func parallelDepth() int {
+	return bits.Len(uint(runtime.NumCPU()))
+}
+
+// scale multiplies each element of a by s.
+func scale(a []Fr, s *Fr) {
+	for i := range a {
+		a[i].Mul(&a[i], s)
+	}
+}
+
+// shift multiplies the i-th element of a by s^i.
+func shift(a []Fr, s *Fr) {
+	w := new(Fr).One()
+	for i := range a {
+		a[i].Mul(&a[i], w)
+		w.Mul(w, s)
+	}
+}
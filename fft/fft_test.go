@@ -0,0 +1,168 @@
+package fft
+
+import (
+	"crypto/rand"
+	"testing"
+)
+
+func randVector(n int) []Fr {
+	a := make([]Fr, n)
+	for i := range a {
+		if _, err := a[i].Rand(rand.Reader); err != nil {
+			panic(err)
+		}
+	}
+	return a
+}
+
+// evaluate returns a(x) with Horner's rule.
+func evaluate(a []Fr, x *Fr) *Fr {
+	r := new(Fr)
+	for i := len(a) - 1; i >= 0; i-- {
+		r.Mul(r, x)
+		r.Add(r, &a[i])
+	}
+	return r
+}
+
+func TestDomain(t *testing.T) {
+	for _, size := range []int{0, 3, 6, 1 << (MaxLogSize + 1)} {
+		if _, err := NewDomain(size); err == nil {
+			t.Fatalf("domain of size %d should be rejected", size)
+		}
+	}
+	for logSize := uint(0); logSize <= 12; logSize++ {
+		d, err := NewDomain(1 << logSize)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d.LogSize != logSize {
+			t.Fatal("bad log size")
+		}
+		w := new(Fr).Set(&d.Generator)
+		for i := uint(0); i < logSize; i++ {
+			if w.IsOne() {
+				t.Fatal("generator is not primitive")
+			}
+			w.Square(w)
+		}
+		if !w.IsOne() {
+			t.Fatal("generator^size == 1")
+		}
+		if !new(Fr).Mul(&d.Generator, &d.GeneratorInv).IsOne() {
+			t.Fatal("bad generator inverse")
+		}
+		if !new(Fr).Mul(&d.SizeInv, new(Fr).SetUint64(uint64(d.Size))).IsOne() {
+			t.Fatal("bad size inverse")
+		}
+		w.One()
+		for i := 0; i < d.Size; i++ {
+			if !d.Element(i).Equal(w) {
+				t.Fatal("bad domain element")
+			}
+			w.Mul(w, &d.Generator)
+		}
+	}
+	// largest domain is generated by the root of unity itself
+	w := new(Fr).SetBig(rootOfUnity)
+	for i := 0; i < MaxLogSize-1; i++ {
+		w.Square(w)
+	}
+	if !w.Equal(new(Fr).Neg(new(Fr).One())) {
+		t.Fatal("root of unity should have order 2^28")
+	}
+}
+
+func TestFFTAgainstNaiveEvaluation(t *testing.T) {
+	for logSize := uint(0); logSize <= 6; logSize++ {
+		d, _ := NewDomain(1 << logSize)
+		a := randVector(d.Size)
+		evals := append([]Fr{}, a...)
+		if err := d.FFT(evals); err != nil {
+			t.Fatal(err)
+		}
+		cosetEvals := append([]Fr{}, a...)
+		if err := d.CosetFFT(cosetEvals); err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < d.Size; i++ {
+			x := d.Element(i)
+			if !evals[i].Equal(evaluate(a, x)) {
+				t.Fatal("bad evaluation")
+			}
+			x.Mul(x, &d.CosetShift)
+			if !cosetEvals[i].Equal(evaluate(a, x)) {
+				t.Fatal("bad coset evaluation")
+			}
+		}
+	}
+}
+
+func TestFFTInverse(t *testing.T) {
+	// larger sizes run the concurrent path
+	for _, logSize := range []uint{0, 1, 2, 5, 11, 14} {
+		d, _ := NewDomain(1 << logSize)
+		a := randVector(d.Size)
+		b := append([]Fr{}, a...)
+		if err := d.FFT(b); err != nil {
+			t.Fatal(err)
+		}
+		if err := d.InverseFFT(b); err != nil {
+			t.Fatal(err)
+		}
+		for i := range a {
+			if !a[i].Equal(&b[i]) {
+				t.Fatal("inverse fft should recover coefficients")
+			}
+		}
+		if err := d.CosetFFT(b); err != nil {
+			t.Fatal(err)
+		}
+		if err := d.InverseCosetFFT(b); err != nil {
+			t.Fatal(err)
+		}
+		for i := range a {
+			if !a[i].Equal(&b[i]) {
+				t.Fatal("inverse coset fft should recover coefficients")
+			}
+		}
+	}
+	d, _ := NewDomain(8)
+	if err := d.FFT(make([]Fr, 4)); err == nil {
+		t.Fatal("input with wrong length should be rejected")
+	}
+	if err := d.InverseCosetFFT(make([]Fr, 16)); err == nil {
+		t.Fatal("input with wrong length should be rejected")
+	}
+}
+
+func TestBitReverse(t *testing.T) {
+	a := make([]Fr, 8)
+	for i := range a {
+		a[i].SetUint64(uint64(i))
+	}
+	BitReverse(a)
+	for i, j := range []uint64{0, 4, 2, 6, 1, 5, 3, 7} {
+		if !a[i].Equal(new(Fr).SetUint64(j)) {
+			t.Fatal("bad bit reversal")
+		}
+	}
+	a = randVector(1 << 10)
+	b := append([]Fr{}, a...)
+	BitReverse(b)
+	BitReverse(b)
+	for i := range a {
+		if !a[i].Equal(&b[i]) {
+			t.Fatal("bit reversal is an involution")
+		}
+	}
+}
+
+func BenchmarkFFT(t *testing.B) {
+	d, _ := NewDomain(1 << 16)
+	a := randVector(d.Size)
+	t.ResetTimer()
+	for i := 0; i < t.N; i++ {
+		_ = d.FFT(a)
+	}
+}
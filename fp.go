@@ -1,8 +1,10 @@
 package bn254
 
 import (
+	"encoding/binary"
 	"errors"
 	"math/big"
+	"math/bits"
 )
 
 func fromBytes(in []byte) (*fe, error) {
@@ -56,6 +58,22 @@ func fromBytesUnchecked(in []byte) (*fe, error) {
 	return fe, nil
 }
 
+// fromMontgomeryLE decodes 32 bytes of an element already in Montgomery form with little
+// endian limb and byte order.
+func fromMontgomeryLE(in []byte) (*fe, error) {
+	if len(in) != 32 {
+		return nil, errors.New("input string should be equal 32 bytes")
+	}
+	fe := &fe{}
+	for i := 0; i < 4; i++ {
+		fe[i] = binary.LittleEndian.Uint64(in[i*8:])
+	}
+	if !fe.isValid() {
+		return nil, errors.New("must be less than modulus")
+	}
+	return fe, nil
+}
+
 func fromBig(in *big.Int) (*fe, error) {
 	fe := new(fe).setBig(in)
 	if !fe.isValid() {
@@ -175,6 +193,18 @@ func inverse(inv, e *fe) {
 	inv.set(u)
 }
 
+// cneg assigns -a to c if cond is one and a if cond is zero in constant time.
+func cneg(c, a *fe, cond uint64) {
+	t := new(fe)
+	sub(t, t, a)
+	c.set(a).cmov(t, cond)
+}
+
+// inverseCT sets c to a^(-1) in constant time. Inverse of zero is zero.
+func inverseCT(c, a *fe) {
+	exp(c, a, pMinus2)
+}
+
 func sqrt(c, a *fe) bool {
 	u, v := new(fe).set(a), new(fe)
 	exp(c, a, pPlus1Over4)
@@ -182,18 +212,75 @@ func sqrt(c, a *fe) bool {
 	return u.equal(v)
 }
 
+// sqrtCT sets c to a square root of a in constant time. Returns all ones if a is a
+// quadratic residue and zero otherwise.
+func sqrtCT(c, a *fe) uint64 {
+	u, v := new(fe).set(a), new(fe)
+	exp(c, a, pPlus1Over4)
+	square(v, c)
+	return u.equalMask(v)
+}
+
 func isQuadraticNonResidue(e *fe) bool {
-	result := new(fe)
-	exp(result, e, pMinus1Over2)
-	return !result.isOne()
+	return legendre(e) != 1
 }
 
+// legendre returns 1 if e is a non zero square, -1 if it is a non square and 0 if it
+// is zero. Symbol is computed with the binary Jacobi algorithm on the Montgomery form of
+// the element, R is an even power of two so it does not change the symbol. Runs in
+// variable time.
 func legendre(e *fe) int {
-	if e.isZero() {
+	a, b := *e, modulus
+	if a.isZero() {
 		return 0
 	}
-	if isQuadraticNonResidue(e) {
-		return -1
+	// least significant bit of s accumulates sign flips
+	var s uint64
+	for {
+		if a[1]|a[2]|a[3]|b[1]|b[2]|b[3] == 0 {
+			return jacobi64(a[0], b[0], s)
+		}
+		for a[0] == 0 {
+			a[0], a[1], a[2], a[3] = a[1], a[2], a[3], 0
+		}
+		// (2 / b) = -1 if b = 3, 5 mod 8
+		z := uint(bits.TrailingZeros64(a[0]))
+		a[0] = a[0]>>z | a[1]<<(64-z)
+		a[1] = a[1]>>z | a[2]<<(64-z)
+		a[2] = a[2]>>z | a[3]<<(64-z)
+		a[3] = a[3] >> z
+		s ^= uint64(z) & (b[0]>>1 ^ b[0]>>2)
+		// (a / b) = -(b / a) if a = b = 3 mod 4
+		if a.cmp(&b) == -1 {
+			a, b = b, a
+			s ^= a[0] & b[0] >> 1
+		}
+		var borrow uint64
+		a[0], borrow = bits.Sub64(a[0], b[0], 0)
+		a[1], borrow = bits.Sub64(a[1], b[1], borrow)
+		a[2], borrow = bits.Sub64(a[2], b[2], borrow)
+		a[3], _ = bits.Sub64(a[3], b[3], borrow)
+		if a.isZero() {
+			return 0
+		}
+	}
+}
+
+// jacobi64 finishes the Jacobi symbol computation of legendre once operands fit into a
+// single word.
+func jacobi64(a, b, s uint64) int {
+	for a != 0 {
+		z := uint(bits.TrailingZeros64(a))
+		a >>= z
+		s ^= uint64(z) & (b>>1 ^ b>>2)
+		if a < b {
+			a, b = b, a
+			s ^= a & b >> 1
+		}
+		a -= b
+	}
+	if b != 1 {
+		return 0
 	}
-	return 1
+	return 1 - 2*int(s&1)
 }
@@ -14,6 +14,7 @@ type fp12temp struct {
 	t2  [9]*fe2
 	t6  [5]*fe6
 	t12 *fe12
+	wt  [3]*wfe6
 }
 
 func newFp12Temp() fp12temp {
@@ -25,7 +26,11 @@ func newFp12Temp() fp12temp {
 	for i := 0; i < len(t6); i++ {
 		t6[i] = &fe6{}
 	}
-	return fp12temp{t2, t6, &fe12{}}
+	wt := [3]*wfe6{}
+	for i := 0; i < len(wt); i++ {
+		wt[i] = &wfe6{}
+	}
+	return fp12temp{t2, t6, &fe12{}, wt}
 }
 
 func newFp12(fp6 *fp6) *fp12 {
@@ -109,16 +114,19 @@ func (e *fp12) conjugate(c, a *fe12) {
 }
 
 func (e *fp12) square(c, a *fe12) {
-	fp6, t := e.fp6, e.t6
+	fp6, t, w := e.fp6, e.t6, e.wt
+	// c0 = (a0 + a1)(a0 + a1 * v) - a0a1 - a0a1 * v, c1 = 2a0a1
+	fp6.mulWide(w[0], &a[0], &a[1])
 	fp6.add(t[0], &a[0], &a[1])
-	fp6.mul(t[2], &a[0], &a[1])
 	fp6.mulByNonResidue(t[1], &a[1])
 	fp6.addAssign(t[1], &a[0])
-	fp6.mulByNonResidue(t[3], t[2])
-	fp6.mulAssign(t[0], t[1])
-	fp6.subAssign(t[0], t[2])
-	fp6.sub(&c[0], t[0], t[3])
-	fp6.double(&c[1], t[2])
+	fp6.mulWide(w[1], t[0], t[1])
+	fp6.subWide(w[1], w[1], w[0])
+	fp6.mulByNonResidueWide(w[2], w[0])
+	fp6.subWide(w[1], w[1], w[2])
+	fp6.addWide(w[0], w[0], w[0])
+	fp6.reduce(&c[0], w[1])
+	fp6.reduce(&c[1], w[0])
 }
 
 func (e *fp12) cyclotomicSquare(c, a *fe12) {
@@ -148,31 +156,37 @@ func (e *fp12) cyclotomicSquare(c, a *fe12) {
 }
 
 func (e *fp12) mul(c, a, b *fe12) {
-	t, fp6 := e.t6, e.fp6
-	fp6.mul(t[1], &a[0], &b[0])
-	fp6.mul(t[2], &a[1], &b[1])
-	fp6.add(t[0], t[1], t[2])
-	fp6.mulByNonResidue(t[2], t[2])
-	fp6.add(t[3], t[1], t[2])
-	fp6.add(t[1], &a[0], &a[1])
-	fp6.add(t[2], &b[0], &b[1])
-	fp6.mulAssign(t[1], t[2])
-	c[0].set(t[3])
-	fp6.sub(&c[1], t[1], t[0])
+	e.mulWide(a, b)
+	e.reduce(c)
 }
 
 func (e *fp12) mulAssign(a, b *fe12) {
-	t, fp6 := e.t6, e.fp6
-	fp6.mul(t[1], &a[0], &b[0])
-	fp6.mul(t[2], &a[1], &b[1])
-	fp6.add(t[0], t[1], t[2])
-	fp6.mulByNonResidue(t[2], t[2])
-	fp6.add(t[3], t[1], t[2])
-	fp6.add(t[1], &a[0], &a[1])
-	fp6.add(t[2], &b[0], &b[1])
-	fp6.mulAssign(t[1], t[2])
-	a[0].set(t[3])
-	fp6.sub(&a[1], t[1], t[0])
+	e.mulWide(a, b)
+	e.reduce(a)
+}
+
+// mulWide computes product of two elements without reduction into wide temporaries,
+// coefficients are reduced once with reduce.
+func (e *fp12) mulWide(a, b *fe12) {
+	fp6, t, w := e.fp6, e.t6, e.wt
+	fp6.mulWide(w[0], &a[0], &b[0])
+	fp6.mulWide(w[1], &a[1], &b[1])
+	fp6.add(t[0], &a[0], &a[1])
+	fp6.add(t[1], &b[0], &b[1])
+	fp6.mulWide(w[2], t[0], t[1])
+	// c1 = (a0 + a1)(b0 + b1) - a0b0 - a1b1
+	fp6.subWide(w[2], w[2], w[0])
+	fp6.subWide(w[2], w[2], w[1])
+	// c0 = a0b0 + a1b1 * v
+	fp6.mulByNonResidueWide(w[1], w[1])
+	fp6.addWide(w[0], w[0], w[1])
+}
+
+// reduce applies Montgomery reduction to the result of mulWide.
+func (e *fp12) reduce(c *fe12) {
+	fp6, w := e.fp6, e.wt
+	fp6.reduce(&c[0], w[0])
+	fp6.reduce(&c[1], w[2])
 }
 
 func (e *fp12) fp4Square(c0, c1, a0, a1 *fe2) {
@@ -238,17 +252,165 @@ func (e *fp12) cyclotomicExp(c, a *fe12, s *big.Int) {
 	c.set(z)
 }
 
+// cyclotomicSquareCompressed squares an element of cyclotomic subgroup in Karabina's compressed form
+// https://eprint.iacr.org/2010/542.pdf. Only a[0][1], a[0][2], a[1][0] and a[1][2] are used and written.
+func (e *fp12) cyclotomicSquareCompressed(c, a *fe12) {
+	t, fp2 := e.t2, e.fp2()
+	// t0 = g1^2, t1 = g5^2
+	fp2.square(t[0], &a[0][1])
+	fp2.square(t[1], &a[1][2])
+	// t5 = 2 * g1 * g5
+	fp2.add(t[5], &a[0][1], &a[1][2])
+	fp2.squareAssign(t[5])
+	fp2.subAssign(t[5], t[0])
+	fp2.subAssign(t[5], t[1])
+	// t2 = g3^2, t3 = g2^2
+	fp2.square(t[2], &a[1][0])
+	fp2.square(t[3], &a[0][2])
+	// t4 = 2 * g2 * g3
+	fp2.add(t[4], &a[1][0], &a[0][2])
+	fp2.squareAssign(t[4])
+	fp2.subAssign(t[4], t[2])
+	fp2.subAssign(t[4], t[3])
+	// t6 = nr * g5^2 + g1^2
+	fp2.mulByNonResidue(t[6], t[1])
+	fp2.addAssign(t[6], t[0])
+	// t7 = g3^2 + nr * g2^2
+	fp2.mulByNonResidue(t[7], t[3])
+	fp2.addAssign(t[7], t[2])
+	// t8 = 2 * nr * g1 * g5
+	fp2.mulByNonResidue(t[8], t[5])
+	// g3' = 6 * nr * g1 * g5 + 2 * g3
+	fp2.add(t[0], t[8], &a[1][0])
+	fp2.doubleAssign(t[0])
+	fp2.add(&c[1][0], t[0], t[8])
+	// g2' = 3 * (nr * g5^2 + g1^2) - 2 * g2
+	fp2.sub(t[0], t[6], &a[0][2])
+	fp2.doubleAssign(t[0])
+	fp2.add(&c[0][2], t[0], t[6])
+	// g1' = 3 * (g3^2 + nr * g2^2) - 2 * g1
+	fp2.sub(t[0], t[7], &a[0][1])
+	fp2.doubleAssign(t[0])
+	fp2.add(&c[0][1], t[0], t[7])
+	// g5' = 6 * g2 * g3 + 2 * g5
+	fp2.add(t[0], t[4], &a[1][2])
+	fp2.doubleAssign(t[0])
+	fp2.add(&c[1][2], t[0], t[4])
+}
+
+// decompressKarabina recovers a[0][0] and a[1][1] of elements in Karabina's compressed form.
+// Denominators are inverted simultaneously so that a single inversion is applied for all elements.
+func (e *fp12) decompressKarabina(a []fe12) {
+	fp2 := e.fp2()
+	t := e.t2
+	n := len(a)
+	num, den := make([]fe2, n), make([]fe2, n)
+	for i := 0; i < n; i++ {
+		if a[i][1][0].isZero() {
+			// g4 = 2 * g1 * g5 / g2
+			fp2.mul(&num[i], &a[i][0][1], &a[i][1][2])
+			fp2.doubleAssign(&num[i])
+			den[i].set(&a[i][0][2])
+		} else {
+			// g4 = (nr * g5^2 + 3 * g1^2 - 2 * g2) / (4 * g3)
+			fp2.square(t[0], &a[i][0][1])
+			fp2.sub(t[1], t[0], &a[i][0][2])
+			fp2.doubleAssign(t[1])
+			fp2.addAssign(t[1], t[0])
+			fp2.square(t[0], &a[i][1][2])
+			fp2.mulByNonResidue(t[0], t[0])
+			fp2.add(&num[i], t[0], t[1])
+			fp2.double(&den[i], &a[i][1][0])
+			fp2.doubleAssign(&den[i])
+		}
+	}
+	// batch inversion, zero denominators are left as zero
+	prefix := make([]fe2, n)
+	acc := fp2.one()
+	for i := 0; i < n; i++ {
+		prefix[i].set(acc)
+		if !den[i].isZero() {
+			fp2.mulAssign(acc, &den[i])
+		}
+	}
+	fp2.inverse(acc, acc)
+	for i := n - 1; i >= 0; i-- {
+		if den[i].isZero() {
+			continue
+		}
+		fp2.mul(t[0], acc, &prefix[i])
+		fp2.mulAssign(acc, &den[i])
+		den[i].set(t[0])
+	}
+	one := fp2.one()
+	for i := 0; i < n; i++ {
+		// g4 = num / den
+		fp2.mul(&a[i][1][1], &num[i], &den[i])
+		// g0 = nr * (2 * g4^2 + g3 * g5 - 3 * g1 * g2) + 1
+		fp2.mul(t[0], &a[i][0][1], &a[i][0][2])
+		fp2.square(t[1], &a[i][1][1])
+		fp2.subAssign(t[1], t[0])
+		fp2.doubleAssign(t[1])
+		fp2.subAssign(t[1], t[0])
+		fp2.mul(t[0], &a[i][1][0], &a[i][1][2])
+		fp2.addAssign(t[1], t[0])
+		fp2.mulByNonResidue(&a[i][0][0], t[1])
+		fp2.addAssign(&a[i][0][0], one)
+	}
+}
+
+// cyclotomicExpCompressed exponentiates an element of cyclotomic subgroup using Karabina's
+// compressed squarings over non adjacent form of the exponent. Squares at non zero digits
+// are decompressed simultaneously and inverted by conjugation where the digit is negative.
+// It pays off for long exponents with sparse non adjacent form such as 6u^2 of the GT subgroup
+// check, plain cyclotomicExp is faster for u.
+func (e *fp12) cyclotomicExpCompressed(c, a *fe12, s *big.Int) {
+	k := new(big.Int).Set(s)
+	var digits []int
+	for k.Sign() > 0 {
+		d := 0
+		if k.Bit(0) == 1 {
+			// d = 1 if k = 1 mod 4 and d = -1 if k = 3 mod 4
+			d = 1 - 2*int(k.Bit(1))
+			k.Sub(k, big.NewInt(int64(d)))
+		}
+		digits = append(digits, d)
+		k.Rsh(k, 1)
+	}
+	squares := make([]fe12, 0, len(digits))
+	signs := make([]int, 0, len(digits))
+	t := new(fe12).set(a)
+	for i, d := range digits {
+		if i > 0 {
+			e.cyclotomicSquareCompressed(t, t)
+		}
+		if d != 0 {
+			squares = append(squares, *t)
+			signs = append(signs, d)
+		}
+	}
+	e.decompressKarabina(squares)
+	z := e.one()
+	for i := 0; i < len(squares); i++ {
+		if signs[i] < 0 {
+			e.conjugate(&squares[i], &squares[i])
+		}
+		e.mulAssign(z, &squares[i])
+	}
+	c.set(z)
+}
+
 func (e *fp12) frobeniusMap(c, a *fe12, power uint) {
 	fp6 := e.fp6
 	fp6.frobeniusMap(&c[0], &a[0], power)
 	fp6.frobeniusMap(&c[1], &a[1], power)
-	switch power {
+	switch power % 12 {
 	case 0:
 		return
 	case 6:
 		fp6.neg(&c[1], &c[1])
 	default:
-		fp6.mulByBaseField(&c[1], &c[1], &frobeniusCoeffs12[power])
+		fp6.mulByBaseField(&c[1], &c[1], &frobeniusCoeffs12[power%12])
 	}
 }
 
@@ -256,12 +418,62 @@ func (e *fp12) frobeniusMapAssign(a *fe12, power uint) {
 	fp6 := e.fp6
 	fp6.frobeniusMapAssign(&a[0], power)
 	fp6.frobeniusMapAssign(&a[1], power)
-	switch power {
+	switch power % 12 {
 	case 0:
 		return
 	case 6:
 		fp6.neg(&a[1], &a[1])
 	default:
-		fp6.mulByBaseField(&a[1], &a[1], &frobeniusCoeffs12[power])
+		fp6.mulByBaseField(&a[1], &a[1], &frobeniusCoeffs12[power%12])
+	}
+}
+
+// isCyclotomic checks whether given element is in cyclotomic subgroup
+// that is the subgroup of order p^4 - p^2 + 1.
+func (e *fp12) isCyclotomic(a *fe12) bool {
+	if a.isZero() {
+		return false
+	}
+	t0, t1 := e.t12, new(fe12)
+	// a^(p^4) * a == a^(p^2)
+	e.frobeniusMap(t0, a, 4)
+	e.mulAssign(t0, a)
+	e.frobeniusMap(t1, a, 2)
+	return t0.equal(t1)
+}
+
+// compress applies torus T2 compression to an element of norm one,
+// a = c0 + c1 * w is mapped to m = (1 + c0) / c1. Identity is mapped to zero.
+func (e *fp12) compress(m *fe6, a *fe12) {
+	fp6 := e.fp6
+	if a[1].isZero() {
+		m.zero()
+		return
+	}
+	t := e.t6
+	fp6.inverse(t[0], &a[1])
+	t[1].set(&a[0])
+	fp2 := e.fp2()
+	fp2.addAssign(&t[1][0], fp2.one())
+	fp6.mul(m, t[1], t[0])
+}
+
+// decompress recovers an element compressed with T2 compression,
+// m is mapped to (m + w) / (m - w) = (m^2 + v + 2 * m * w) / (m^2 - v). Zero is mapped to identity.
+func (e *fp12) decompress(c *fe12, m *fe6) {
+	fp6, fp2 := e.fp6, e.fp2()
+	if m.isZero() {
+		c.one()
+		return
 	}
+	t := e.t6
+	one := fp2.one()
+	fp6.square(t[0], m)
+	t[1].set(t[0])
+	fp2.subAssign(&t[1][1], one)
+	fp2.addAssign(&t[0][1], one)
+	fp6.inverse(t[1], t[1])
+	fp6.double(t[2], m)
+	fp6.mul(&c[0], t[0], t[1])
+	fp6.mul(&c[1], t[2], t[1])
 }
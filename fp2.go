@@ -6,7 +6,9 @@ import (
 )
 
 type fp2Temp struct {
-	t [4]*fe
+	t  [4]*fe
+	wt [4]*wfe
+	w2 *wfe2
 }
 
 type fp2 struct {
@@ -18,7 +20,11 @@ func newFp2Temp() fp2Temp {
 	for i := 0; i < len(t); i++ {
 		t[i] = &fe{}
 	}
-	return fp2Temp{t}
+	wt := [4]*wfe{}
+	for i := 0; i < len(wt); i++ {
+		wt[i] = &wfe{}
+	}
+	return fp2Temp{t, wt, &wfe2{}}
 }
 
 func newFp2() *fp2 {
@@ -110,6 +116,12 @@ func (e *fp2) neg(c, a *fe2) {
 	neg(&c[1], &a[1])
 }
 
+// cneg assigns -a to c if cond is one and a if cond is zero in constant time.
+func (e *fp2) cneg(c, a *fe2, cond uint64) {
+	cneg(&c[0], &a[0], cond)
+	cneg(&c[1], &a[1], cond)
+}
+
 func (e *fp2) conjugate(c, a *fe2) *fe2 {
 	c.set(a)
 	neg(&c[1], &a[1])
@@ -117,27 +129,15 @@ func (e *fp2) conjugate(c, a *fe2) *fe2 {
 }
 
 func (e *fp2) mul(c, a, b *fe2) {
-	t := e.t
-	mul(t[1], &a[0], &b[0])
-	mul(t[2], &a[1], &b[1])
-	add(t[0], &a[0], &a[1])
-	add(t[3], &b[0], &b[1])
-	sub(&c[0], t[1], t[2])
-	addAssign(t[1], t[2])
-	mul(t[0], t[0], t[3])
-	sub(&c[1], t[0], t[1])
+	w := e.w2
+	e.mulWide(w, a, b)
+	e.reduce(c, w)
 }
 
 func (e *fp2) mulAssign(a, b *fe2) {
-	t := e.t
-	mul(t[1], &a[0], &b[0])
-	mul(t[2], &a[1], &b[1])
-	add(t[0], &a[0], &a[1])
-	add(t[3], &b[0], &b[1])
-	sub(&a[0], t[1], t[2])
-	addAssign(t[1], t[2])
-	mul(t[0], t[0], t[3])
-	sub(&a[1], t[0], t[1])
+	w := e.w2
+	e.mulWide(w, a, b)
+	e.reduce(a, w)
 }
 
 func (e *fp2) square(c, a *fe2) {
@@ -174,6 +174,67 @@ func (e *fp2) mulByNonResidue(c, a *fe2) {
 	c[1].set(t[0])
 }
 
+// mulWide computes product of two elements without reduction,
+// coefficients are reduced once with reduce.
+func (e *fp2) mulWide(c *wfe2, a, b *fe2) {
+	t, w := e.t, e.wt
+	wmul(w[0], &a[0], &b[0])
+	wmul(w[1], &a[1], &b[1])
+	// (a0 + a1) * (b0 + b1) < 4p^2 fits into wide range without reduction of sums
+	t[0].set(&a[0])
+	laddAssign(t[0], &a[1])
+	t[1].set(&b[0])
+	laddAssign(t[1], &b[1])
+	wmul(w[2], t[0], t[1])
+	wsub(w[2], w[2], w[0])
+	wsub(&c[1], w[2], w[1])
+	wsub(&c[0], w[0], w[1])
+}
+
+// squareWide computes square of an element without reduction.
+func (e *fp2) squareWide(c *wfe2, a *fe2) {
+	t := e.t
+	add(t[0], &a[0], &a[1])
+	sub(t[1], &a[0], &a[1])
+	double(t[2], &a[0])
+	wmul(&c[1], t[2], &a[1])
+	wmul(&c[0], t[0], t[1])
+}
+
+// reduce applies Montgomery reduction to both coefficients of a wide element.
+func (e *fp2) reduce(c *fe2, a *wfe2) {
+	montRed(&c[0], &a[0])
+	montRed(&c[1], &a[1])
+}
+
+func (e *fp2) addWide(c, a, b *wfe2) {
+	wadd(&c[0], &a[0], &b[0])
+	wadd(&c[1], &a[1], &b[1])
+}
+
+func (e *fp2) subWide(c, a, b *wfe2) {
+	wsub(&c[0], &a[0], &b[0])
+	wsub(&c[1], &a[1], &b[1])
+}
+
+// mulByNonResidueWide multiplies a wide element by non residue 9 + u.
+func (e *fp2) mulByNonResidueWide(c, a *wfe2) {
+	w := e.wt
+	// w0 = 9 * a0, w1 = 9 * a1
+	wadd(w[0], &a[0], &a[0])
+	wadd(w[1], &a[1], &a[1])
+	wadd(w[0], w[0], w[0])
+	wadd(w[1], w[1], w[1])
+	wadd(w[0], w[0], w[0])
+	wadd(w[1], w[1], w[1])
+	wadd(w[0], w[0], &a[0])
+	wadd(w[1], w[1], &a[1])
+	// c0 = 9 * a0 - a1, c1 = a0 + 9 * a1
+	wsub(w[0], w[0], &a[1])
+	wadd(&c[1], w[1], &a[0])
+	c[0] = *w[0]
+}
+
 func (e *fp2) mulByB(c, a *fe2) {
 	t := e.t
 	double(t[0], &a[0])
@@ -227,29 +288,70 @@ func (e *fp2) frobeniusMapAssign(a *fe2, power uint) {
 	}
 }
 
+// sqrt sets c to the square root of a with sgn0 equal to zero and returns true if a is a
+// square. Non squares are rejected early with the Legendre symbol of the norm.
 func (e *fp2) sqrt(c, a *fe2) bool {
-	u, x0, a1, alpha := &fe2{}, &fe2{}, &fe2{}, &fe2{}
-	u.set(a)
-	e.exp(a1, a, pMinus3Over4)
-	e.square(alpha, a1)
-	e.mul(alpha, alpha, a)
-	e.mul(x0, a1, a)
-	if alpha.equal(negativeOne2) {
-		neg(&c[0], &x0[1])
-		c[1].set(&x0[0])
-		return true
+	if e.legendre(a) == -1 {
+		return false
 	}
-	e.add(alpha, alpha, e.one())
-	e.exp(alpha, alpha, pMinus1Over2)
-	e.mul(c, alpha, x0)
-	e.square(alpha, c)
-	return alpha.equal(u)
+	return e.sqrtCT(c, a) != 0
+}
+
+// sqrtCT sets c to the square root of a with sgn0 equal to zero and returns all ones if a
+// is a square and zero otherwise. It follows the complex method where the root of
+// a = a0 + a1 * u is x0 + x1 * u with x0^2 = (a0 +- sqrt(a0^2 + a1^2)) / 2 and
+// x1 = a1 / 2 * x0. Both roots and the inversion are derived from base field
+// exponentiations by (p - 3) / 4. The second exponentiation gives x0 and its inverse
+// at once, it can not be merged with the first one since its base depends on the root
+// of the norm. Runs in constant time.
+func (e *fp2) sqrtCT(c, a *fe2) uint64 {
+	u := new(fe2).set(a)
+	alpha, gamma, delta, t, s := new(fe), new(fe), new(fe), new(fe), new(fe)
+	// gamma = sqrt(a0^2 + a1^2)
+	square(alpha, &a[0])
+	square(s, &a[1])
+	add(alpha, alpha, s)
+	exp(s, alpha, pMinus3Over4)
+	mul(gamma, alpha, s)
+	// delta = (a0 + gamma) / 2, which is zero only if a1 is zero and then
+	// delta = (a0 - gamma) / 2 is used instead
+	add(delta, &a[0], gamma)
+	mul(delta, delta, twoInv)
+	sub(s, &a[0], gamma)
+	mul(s, s, twoInv)
+	delta.cmov(s, delta.isZeroMask())
+	// t = delta^((p - 3) / 4) so that delta * t^2 is the quadratic character of delta
+	exp(t, delta, pMinus3Over4)
+	square(s, t)
+	mul(s, s, delta)
+	isSquare := s.equalMask(one)
+	// if delta is a square x0 = delta * t and x1 = a1 * t / 2 otherwise -1 * delta
+	// is a square since product of both choices is -(a1 / 2)^2 and then
+	// x0 = -a1 * t / 2 and x1 = delta * t
+	r, y := new(fe2), new(fe)
+	mul(&r[0], delta, t)
+	mul(&r[1], &a[1], t)
+	mul(&r[1], &r[1], twoInv)
+	cneg(y, &r[1], 1)
+	y.cmov(&r[0], isSquare)
+	r[1].cmov(&r[0], ^isSquare)
+	r[0].set(y)
+	e.cneg(r, r, r.sgn0())
+	c.set(r)
+	e.square(r, r)
+	return r.equalMask(u)
 }
 
 func (e *fp2) isQuadraticNonResidue(a *fe2) bool {
+	return e.legendre(a) != 1
+}
+
+// legendre returns the quadratic character of a, which is equal to the Legendre symbol of
+// its norm a0^2 + a1^2 in the base field.
+func (e *fp2) legendre(a *fe2) int {
 	c0, c1 := new(fe), new(fe)
 	square(c0, &a[0])
 	square(c1, &a[1])
 	add(c1, c1, c0)
-	return isQuadraticNonResidue(c1)
+	return legendre(c1)
 }
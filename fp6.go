@@ -6,7 +6,9 @@ import (
 )
 
 type fp6Temp struct {
-	t [6]*fe2
+	t  [6]*fe2
+	wt [6]*wfe2
+	w6 *wfe6
 }
 
 type fp6 struct {
@@ -19,7 +21,11 @@ func newFp6Temp() fp6Temp {
 	for i := 0; i < len(t); i++ {
 		t[i] = &fe2{}
 	}
-	return fp6Temp{t}
+	wt := [6]*wfe2{}
+	for i := 0; i < len(wt); i++ {
+		wt[i] = &wfe2{}
+	}
+	return fp6Temp{t, wt, &wfe6{}}
 }
 
 func newFp6(f *fp2) *fp6 {
@@ -128,80 +134,109 @@ func (e *fp6) conjugate(c, a *fe6) {
 }
 
 func (e *fp6) mul(c, a, b *fe6) {
-	fp2, t := e.fp2, e.t
-	fp2.mul(t[0], &a[0], &b[0])
-	fp2.mul(t[1], &a[1], &b[1])
-	fp2.mul(t[2], &a[2], &b[2])
-	fp2.add(t[3], &a[1], &a[2])
-	fp2.add(t[4], &b[1], &b[2])
-	fp2.mulAssign(t[3], t[4])
-	fp2.add(t[4], t[1], t[2])
-	fp2.subAssign(t[3], t[4])
-	fp2.mulByNonResidue(t[3], t[3])
-	fp2.add(t[5], t[0], t[3])
-	fp2.add(t[3], &a[0], &a[1])
-	fp2.add(t[4], &b[0], &b[1])
-	fp2.mulAssign(t[3], t[4])
-	fp2.add(t[4], t[0], t[1])
-	fp2.subAssign(t[3], t[4])
-	fp2.mulByNonResidue(t[4], t[2])
-	fp2.add(&c[1], t[3], t[4])
-	fp2.add(t[3], &a[0], &a[2])
-	fp2.add(t[4], &b[0], &b[2])
-	fp2.mulAssign(t[3], t[4])
-	fp2.add(t[4], t[0], t[2])
-	fp2.subAssign(t[3], t[4])
-	fp2.add(&c[2], t[1], t[3])
-	c[0].set(t[5])
+	w := e.w6
+	e.mulWide(w, a, b)
+	e.reduce(c, w)
 }
 
 func (e *fp6) mulAssign(a, b *fe6) {
-	fp2, t := e.fp2, e.t
-	fp2.mul(t[0], &a[0], &b[0])
-	fp2.mul(t[1], &a[1], &b[1])
-	fp2.mul(t[2], &a[2], &b[2])
-	fp2.add(t[3], &a[1], &a[2])
-	fp2.add(t[4], &b[1], &b[2])
-	fp2.mulAssign(t[3], t[4])
-	fp2.add(t[4], t[1], t[2])
-	fp2.subAssign(t[3], t[4])
-	fp2.mulByNonResidue(t[3], t[3])
-	fp2.add(t[5], t[0], t[3])
-	fp2.add(t[3], &a[0], &a[1])
-	fp2.add(t[4], &b[0], &b[1])
-	fp2.mulAssign(t[3], t[4])
-	fp2.add(t[4], t[0], t[1])
-	fp2.subAssign(t[3], t[4])
-	fp2.mulByNonResidue(t[4], t[2])
-	fp2.add(&a[1], t[3], t[4])
-	fp2.add(t[3], &a[0], &a[2])
-	fp2.add(t[4], &b[0], &b[2])
-	fp2.mulAssign(t[3], t[4])
-	fp2.add(t[4], t[0], t[2])
-	fp2.subAssign(t[3], t[4])
-	fp2.add(&a[2], t[1], t[3])
-	a[0].set(t[5])
+	w := e.w6
+	e.mulWide(w, a, b)
+	e.reduce(a, w)
 }
 
 func (e *fp6) square(c, a *fe6) {
-	fp2, t := e.fp2, e.t
-	fp2.square(t[0], &a[0])
-	fp2.mul(t[1], &a[0], &a[1])
-	fp2.doubleAssign(t[1])
-	fp2.sub(t[2], &a[0], &a[1])
-	fp2.addAssign(t[2], &a[2])
-	fp2.squareAssign(t[2])
-	fp2.mul(t[3], &a[1], &a[2])
-	fp2.doubleAssign(t[3])
-	fp2.square(t[4], &a[2])
-	fp2.mulByNonResidue(t[5], t[3])
-	fp2.add(&c[0], t[0], t[5])
-	fp2.mulByNonResidue(t[5], t[4])
-	fp2.add(&c[1], t[1], t[5])
-	fp2.addAssign(t[1], t[2])
-	fp2.addAssign(t[1], t[3])
-	fp2.addAssign(t[0], t[4])
-	fp2.sub(&c[2], t[1], t[0])
+	w := e.w6
+	e.squareWide(w, a)
+	e.reduce(c, w)
+}
+
+// mulWide computes product of two elements without reduction,
+// coefficients are reduced once with reduce.
+func (e *fp6) mulWide(c *wfe6, a, b *fe6) {
+	fp2, t, w := e.fp2, e.t, e.wt
+	fp2.mulWide(w[0], &a[0], &b[0])
+	fp2.mulWide(w[1], &a[1], &b[1])
+	fp2.mulWide(w[2], &a[2], &b[2])
+	// c0 = a0b0 + ((a1 + a2)(b1 + b2) - a1b1 - a2b2) * nr
+	fp2.add(t[0], &a[1], &a[2])
+	fp2.add(t[1], &b[1], &b[2])
+	fp2.mulWide(w[3], t[0], t[1])
+	fp2.subWide(w[3], w[3], w[1])
+	fp2.subWide(w[3], w[3], w[2])
+	fp2.mulByNonResidueWide(w[3], w[3])
+	// c1 = (a0 + a1)(b0 + b1) - a0b0 - a1b1 + a2b2 * nr
+	fp2.add(t[0], &a[0], &a[1])
+	fp2.add(t[1], &b[0], &b[1])
+	fp2.mulWide(w[4], t[0], t[1])
+	fp2.subWide(w[4], w[4], w[0])
+	fp2.subWide(w[4], w[4], w[1])
+	fp2.mulByNonResidueWide(w[5], w[2])
+	fp2.addWide(&c[1], w[4], w[5])
+	// c2 = (a0 + a2)(b0 + b2) - a0b0 - a2b2 + a1b1
+	fp2.add(t[0], &a[0], &a[2])
+	fp2.add(t[1], &b[0], &b[2])
+	fp2.mulWide(w[4], t[0], t[1])
+	fp2.subWide(w[4], w[4], w[0])
+	fp2.subWide(w[4], w[4], w[2])
+	fp2.addWide(&c[2], w[4], w[1])
+	fp2.addWide(&c[0], w[0], w[3])
+}
+
+// squareWide computes square of an element without reduction.
+func (e *fp6) squareWide(c *wfe6, a *fe6) {
+	fp2, t, w := e.fp2, e.t, e.wt
+	// s0 = a0^2, s1 = 2a0a1, s2 = (a0 - a1 + a2)^2, s3 = 2a1a2, s4 = a2^2
+	fp2.squareWide(w[0], &a[0])
+	fp2.double(t[0], &a[0])
+	fp2.mulWide(w[1], t[0], &a[1])
+	fp2.sub(t[0], &a[0], &a[1])
+	fp2.addAssign(t[0], &a[2])
+	fp2.squareWide(w[2], t[0])
+	fp2.double(t[0], &a[1])
+	fp2.mulWide(w[3], t[0], &a[2])
+	fp2.squareWide(w[4], &a[2])
+	// c2 = s1 + s2 + s3 - s0 - s4
+	fp2.addWide(w[2], w[2], w[1])
+	fp2.addWide(w[2], w[2], w[3])
+	fp2.subWide(w[2], w[2], w[0])
+	fp2.subWide(&c[2], w[2], w[4])
+	// c0 = s0 + s3 * nr, c1 = s1 + s4 * nr
+	fp2.mulByNonResidueWide(w[3], w[3])
+	fp2.addWide(&c[0], w[0], w[3])
+	fp2.mulByNonResidueWide(w[4], w[4])
+	fp2.addWide(&c[1], w[1], w[4])
+}
+
+// reduce applies Montgomery reduction to all coefficients of a wide element.
+func (e *fp6) reduce(c *fe6, a *wfe6) {
+	fp2 := e.fp2
+	fp2.reduce(&c[0], &a[0])
+	fp2.reduce(&c[1], &a[1])
+	fp2.reduce(&c[2], &a[2])
+}
+
+func (e *fp6) addWide(c, a, b *wfe6) {
+	fp2 := e.fp2
+	fp2.addWide(&c[0], &a[0], &b[0])
+	fp2.addWide(&c[1], &a[1], &b[1])
+	fp2.addWide(&c[2], &a[2], &b[2])
+}
+
+func (e *fp6) subWide(c, a, b *wfe6) {
+	fp2 := e.fp2
+	fp2.subWide(&c[0], &a[0], &b[0])
+	fp2.subWide(&c[1], &a[1], &b[1])
+	fp2.subWide(&c[2], &a[2], &b[2])
+}
+
+// mulByNonResidueWide multiplies a wide element by non residue v.
+func (e *fp6) mulByNonResidueWide(c, a *wfe6) {
+	fp2, w := e.fp2, e.wt
+	fp2.mulByNonResidueWide(w[0], &a[2])
+	c[2] = a[1]
+	c[1] = a[0]
+	c[0] = *w[0]
 }
 
 func (e *fp6) mulBy01Assign(a *fe6, b0, b1 *fe2) {
@@ -283,10 +318,6 @@ func (e *fp6) frobeniusMap(c, a *fe6, power uint) {
 	switch power % 6 {
 	case 0:
 		return
-	case 3:
-		neg(&c[0][0], &a[1][1])
-		c[1][1].set(&a[1][0])
-		fp2.neg(&a[2], &a[2])
 	default:
 		fp2.mul(&c[1], &c[1], &frobeniusCoeffs61[power%6])
 		fp2.mul(&c[2], &c[2], &frobeniusCoeffs62[power%6])
@@ -298,15 +329,9 @@ func (e *fp6) frobeniusMapAssign(a *fe6, power uint) {
 	fp2.frobeniusMapAssign(&a[0], power)
 	fp2.frobeniusMapAssign(&a[1], power)
 	fp2.frobeniusMapAssign(&a[2], power)
-	t := e.t
 	switch power % 6 {
 	case 0:
 		return
-	case 3:
-		neg(&t[0][0], &a[1][1])
-		a[1][1].set(&a[1][0])
-		a[1][0].set(&t[0][0])
-		fp2.neg(&a[2], &a[2])
 	default:
 		fp2.mulAssign(&a[1], &frobeniusCoeffs61[power%6])
 		fp2.mulAssign(&a[2], &frobeniusCoeffs62[power%6])
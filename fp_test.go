@@ -279,6 +279,21 @@ func TestFpMultiplicationCrossAgainstBigInt(t *testing.T) {
 	}
 }
 
+func TestFpSquareCrossAgainstBigInt(t *testing.T) {
+	for i := 0; i < fuz; i++ {
+		a, _ := new(fe).rand(rand.Reader)
+		c := new(fe)
+		big_a := toBig(a)
+		big_c := new(big.Int)
+		square(c, a)
+		out_1 := toBytes(c)
+		out_2 := padBytes(big_c.Mul(big_a, big_a).Mod(big_c, modulus.big()).Bytes(), 32)
+		if !bytes.Equal(out_1, out_2) {
+			t.Fatal("cross test against big.Int is not satisfied")
+		}
+	}
+}
+
 func TestFpMultiplicationProperties(t *testing.T) {
 	for i := 0; i < fuz; i++ {
 		a, _ := new(fe).rand(rand.Reader)
@@ -323,6 +338,57 @@ func TestFpMultiplicationProperties(t *testing.T) {
 	}
 }
 
+func TestFpWideArithmetic(t *testing.T) {
+	wideToBig := func(a *wfe) *big.Int {
+		r := new(big.Int)
+		for i := len(a) - 1; i >= 0; i-- {
+			r.Lsh(r, 64).Add(r, new(big.Int).SetUint64(a[i]))
+		}
+		return r
+	}
+	bound := new(big.Int).Lsh(modulus.big(), 256)
+	for i := 0; i < fuz; i++ {
+		a, _ := new(fe).rand(rand.Reader)
+		b, _ := new(fe).rand(rand.Reader)
+		c, _ := new(fe).rand(rand.Reader)
+		d, _ := new(fe).rand(rand.Reader)
+		w_1, w_2, w_3 := new(wfe), new(wfe), new(wfe)
+		c_1, c_2, c_3 := new(fe), new(fe), new(fe)
+		wmul(w_1, a, b)
+		montRed(c_1, w_1)
+		mul(c_2, a, b)
+		if !c_1.equal(c_2) {
+			t.Fatal("wide multiplication and reduction is not equal to multiplication")
+		}
+		wmul(w_2, c, d)
+		mul(c_3, c, d)
+		big_1, big_2 := wideToBig(w_1), wideToBig(w_2)
+		wadd(w_3, w_1, w_2)
+		big_3 := new(big.Int).Add(big_1, big_2)
+		big_3.Mod(big_3, bound)
+		if wideToBig(w_3).Cmp(big_3) != 0 {
+			t.Fatal("wide addition is not satisfied")
+		}
+		montRed(c_1, w_3)
+		add(c_2, c_2, c_3)
+		if !c_1.equal(c_2) {
+			t.Fatal("reduction of wide addition is not satisfied")
+		}
+		wsub(w_3, w_1, w_2)
+		big_3.Sub(big_1, big_2)
+		big_3.Mod(big_3, bound)
+		if wideToBig(w_3).Cmp(big_3) != 0 {
+			t.Fatal("wide subtraction is not satisfied")
+		}
+		montRed(c_1, w_3)
+		mul(c_2, a, b)
+		sub(c_2, c_2, c_3)
+		if !c_1.equal(c_2) {
+			t.Fatal("reduction of wide subtraction is not satisfied")
+		}
+	}
+}
+
 func TestFpExponentiation(t *testing.T) {
 	for i := 0; i < fuz; i++ {
 		a, _ := new(fe).rand(rand.Reader)
@@ -402,6 +468,136 @@ func TestFpSquareRoot(t *testing.T) {
 	}
 }
 
+func TestFpConstantTimeSelection(t *testing.T) {
+	for i := 0; i < fuz; i++ {
+		a, _ := new(fe).rand(rand.Reader)
+		b, _ := new(fe).rand(rand.Reader)
+		if a.equalMask(a) != ^uint64(0) || a.equalMask(b) != 0 {
+			t.Fatal("bad equality mask")
+		}
+		if new(fe).isZeroMask() != ^uint64(0) || a.isZeroMask() != 0 {
+			t.Fatal("bad zero mask")
+		}
+		c := new(fe).set(a)
+		if !c.cmov(b, 0).equal(a) {
+			t.Fatal("cmov with zero condition should be noop")
+		}
+		if !c.cmov(b, 1).equal(b) {
+			t.Fatal("cmov with one condition should assign")
+		}
+		c.set(a)
+		if !c.cmov(b, a.equalMask(b)).equal(a) || !c.cmov(b, a.equalMask(a)).equal(b) {
+			t.Fatal("cmov should accept masks")
+		}
+		n := new(fe)
+		neg(n, a)
+		cneg(c, a, 0)
+		if !c.equal(a) {
+			t.Fatal("cneg with zero condition should be noop")
+		}
+		cneg(c, a, 1)
+		if !c.equal(n) {
+			t.Fatal("cneg with one condition should negate")
+		}
+	}
+	c := new(fe)
+	cneg(c, c, 1)
+	if !c.isZero() {
+		t.Fatal("-0 == 0")
+	}
+}
+
+func TestFp2ConstantTimeSelection(t *testing.T) {
+	f := newFp2()
+	for i := 0; i < fuz; i++ {
+		a, _ := new(fe2).rand(rand.Reader)
+		b := new(fe2).set(a)
+		b[1].set(&a[0])
+		if a.equalMask(a) != ^uint64(0) || a.equalMask(b) != 0 {
+			t.Fatal("bad equality mask")
+		}
+		if new(fe2).isZeroMask() != ^uint64(0) || a.isZeroMask() != 0 {
+			t.Fatal("bad zero mask")
+		}
+		c := new(fe2).set(a)
+		if !c.cmov(b, 0).equal(a) || !c.cmov(b, 1).equal(b) {
+			t.Fatal("bad cmov")
+		}
+		n := new(fe2)
+		f.neg(n, a)
+		f.cneg(c, a, 0)
+		if !c.equal(a) {
+			t.Fatal("cneg with zero condition should be noop")
+		}
+		f.cneg(c, a, 1)
+		if !c.equal(n) {
+			t.Fatal("cneg with one condition should negate")
+		}
+	}
+}
+
+func TestFpConstantTimeSquareRoot(t *testing.T) {
+	r := new(fe)
+	if sqrtCT(r, nonResidue1) != 0 {
+		t.Fatal("non residue cannot have a sqrt")
+	}
+	for i := 0; i < fuz; i++ {
+		a, _ := new(fe).rand(rand.Reader)
+		aa, rr := new(fe), new(fe)
+		square(aa, a)
+		if sqrtCT(r, aa) != ^uint64(0) {
+			t.Fatal("bad sqrt 1")
+		}
+		square(rr, r)
+		if !rr.equal(aa) {
+			t.Fatal("bad sqrt 2")
+		}
+		inv := new(fe)
+		inverse(inv, a)
+		inverseCT(r, a)
+		if !r.equal(inv) {
+			t.Fatal("constant time inverse is not equal to inverse")
+		}
+		if toBig(a).Bit(0) != uint(a.sgn0()) {
+			t.Fatal("bad sgn0")
+		}
+	}
+	inverseCT(r, new(fe))
+	if !r.isZero() {
+		t.Fatal("inverse of zero is zero")
+	}
+}
+
+func TestFpLegendre(t *testing.T) {
+	if legendre(zero) != 0 {
+		t.Fatal("legendre of zero is zero")
+	}
+	if legendre(nonResidue1) != -1 {
+		t.Fatal("-1 is a non residue")
+	}
+	edges := []*fe{new(fe).one(), new(fe).set(&modulus), new(fe).set(twoInv)}
+	lsubAssign(edges[1], one)
+	for i := 0; i < fuz; i++ {
+		a, _ := new(fe).rand(rand.Reader)
+		edges = append(edges, a)
+	}
+	for _, a := range edges {
+		if legendre(a) != big.Jacobi(toBig(a), modulus.big()) {
+			t.Fatal("cross test against big.Int is not satisfied")
+		}
+		// Euler's criterion
+		r := new(fe)
+		exp(r, a, pMinus1Over2)
+		if (legendre(a) == 1) != r.isOne() {
+			t.Fatal("legendre symbol does not agree with Euler's criterion")
+		}
+		square(r, a)
+		if !a.isZero() && legendre(r) != 1 {
+			t.Fatal("squares are residues")
+		}
+	}
+}
+
 func TestFp2Serialization(t *testing.T) {
 	field := newFp2()
 	for i := 0; i < fuz; i++ {
@@ -729,6 +925,54 @@ func TestFp2SquareRoot(t *testing.T) {
 	}
 }
 
+func TestFp2ConstantTimeSquareRoot(t *testing.T) {
+	field := newFp2()
+	r := field.new()
+	if field.sqrtCT(r, nonResidue2) != 0 {
+		t.Fatal("non residue cannot have a sqrt")
+	}
+	if field.sqrtCT(r, field.zero()) != ^uint64(0) || !r.isZero() {
+		t.Fatal("sqrt of zero is zero")
+	}
+	a, _ := new(fe).rand(rand.Reader)
+	inputs := []*fe2{
+		// base field elements, a non residue and a residue
+		{*nonResidue1, fe{}},
+		{fe{}, fe{}},
+		// purely imaginary elements
+		{fe{}, *a},
+	}
+	square(&inputs[1][0], a)
+	for i := 0; i < fuz; i++ {
+		a, _ := new(fe2).rand(rand.Reader)
+		inputs = append(inputs, a)
+	}
+	for _, a := range inputs {
+		aa, rr := field.new(), field.new()
+		field.square(aa, a)
+		for _, in := range []*fe2{a, aa} {
+			mask := field.sqrtCT(r, in)
+			if (mask == ^uint64(0)) != (field.legendre(in) != -1) {
+				t.Fatal("square root must exist only for squares")
+			}
+			if mask == 0 {
+				continue
+			}
+			field.square(rr, r)
+			if !rr.equal(in) {
+				t.Fatal("bad sqrt")
+			}
+			if r.sgn0() != 0 {
+				t.Fatal("root with zero sign is expected")
+			}
+			c := field.new()
+			if !field.sqrt(c, in) || !c.equal(r) {
+				t.Fatal("roots of sqrt and sqrtCT must be equal")
+			}
+		}
+	}
+}
+
 func TestFp2NonResidue(t *testing.T) {
 	field := newFp2()
 	if !field.isQuadraticNonResidue(nonResidue2) {
@@ -759,6 +1003,33 @@ func TestFp2NonResidue(t *testing.T) {
 	}
 }
 
+func TestFp2Legendre(t *testing.T) {
+	field := newFp2()
+	if field.legendre(new(fe2).zero()) != 0 {
+		t.Fatal("legendre of zero is zero")
+	}
+	if field.legendre(nonResidue2) != -1 {
+		t.Fatal("element is quadratic non residue")
+	}
+	for i := 0; i < fuz; i++ {
+		a, _ := new(fe2).rand(rand.Reader)
+		b, _ := new(fe2).rand(rand.Reader)
+		c := new(fe2)
+		field.mul(c, a, b)
+		if field.legendre(c) != field.legendre(a)*field.legendre(b) {
+			t.Fatal("legendre symbol is multiplicative")
+		}
+		field.square(c, a)
+		if field.legendre(c) != 1 {
+			t.Fatal("squares are residues")
+		}
+		field.mul(c, c, nonResidue2)
+		if field.legendre(c) != -1 {
+			t.Fatal("non residue times square is non residue")
+		}
+	}
+}
+
 func TestFp6Serialization(t *testing.T) {
 	field := newFp6(nil)
 	for i := 0; i < fuz; i++ {
@@ -974,6 +1245,39 @@ func TestFp6MultiplicationProperties(t *testing.T) {
 	}
 }
 
+func TestFp6MultiplicationCrossAgainstSchoolbook(t *testing.T) {
+	field := newFp6(nil)
+	fp2 := field.fp2
+	for i := 0; i < fuz; i++ {
+		a, _ := new(fe6).rand(rand.Reader)
+		b, _ := new(fe6).rand(rand.Reader)
+		// c_k = sum of a_i * b_j where i + j = k, and v^3 = nr
+		c := [5]fe2{}
+		t_0 := new(fe2)
+		for i := 0; i < 3; i++ {
+			for j := 0; j < 3; j++ {
+				fp2.mul(t_0, &a[i], &b[j])
+				fp2.addAssign(&c[i+j], t_0)
+			}
+		}
+		c_1, c_2 := field.new(), field.new()
+		fp2.mulByNonResidue(t_0, &c[3])
+		fp2.add(&c_1[0], &c[0], t_0)
+		fp2.mulByNonResidue(t_0, &c[4])
+		fp2.add(&c_1[1], &c[1], t_0)
+		c_1[2].set(&c[2])
+		field.mul(c_2, a, b)
+		if !c_1.equal(c_2) {
+			t.Fatal("cross test against schoolbook multiplication is not satisfied")
+		}
+		field.square(c_2, b)
+		field.mul(c_1, b, b)
+		if !c_1.equal(c_2) {
+			t.Fatal("a^2 == a*a")
+		}
+	}
+}
+
 func TestFp6MultiplicationPropertiesAssigned(t *testing.T) {
 	field := newFp6(nil)
 	for i := 0; i < fuz; i++ {
@@ -1278,3 +1582,142 @@ func TestFp12Inversion(t *testing.T) {
 		}
 	}
 }
+
+func TestFp6Frobenius(t *testing.T) {
+	field := newFp6(nil)
+	for i := 0; i < fuz; i++ {
+		a, _ := new(fe6).rand(rand.Reader)
+		u, v := field.new(), field.new()
+		pk := big.NewInt(1)
+		for k := uint(0); k < 12; k++ {
+			field.exp(u, a, pk)
+			field.frobeniusMap(v, a, k)
+			if !u.equal(v) {
+				t.Fatalf("bad frobenius map, power %d", k)
+			}
+			v.set(a)
+			field.frobeniusMapAssign(v, k)
+			if !u.equal(v) {
+				t.Fatalf("bad frobenius map assign, power %d", k)
+			}
+			pk.Mul(pk, pbig)
+		}
+	}
+}
+
+func TestFp12Frobenius(t *testing.T) {
+	field := newFp12(nil)
+	for i := 0; i < fuz; i++ {
+		a, _ := new(fe12).rand(rand.Reader)
+		u, v := field.new(), field.new()
+		pk := big.NewInt(1)
+		for k := uint(0); k < 12; k++ {
+			field.exp(u, a, pk)
+			field.frobeniusMap(v, a, k)
+			if !u.equal(v) {
+				t.Fatalf("bad frobenius map, power %d", k)
+			}
+			v.set(a)
+			field.frobeniusMapAssign(v, k)
+			if !u.equal(v) {
+				t.Fatalf("bad frobenius map assign, power %d", k)
+			}
+			pk.Mul(pk, pbig)
+		}
+		// a^(p^12) == a
+		field.frobeniusMap(v, a, 12)
+		if !v.equal(a) {
+			t.Fatal("bad frobenius map, power 12")
+		}
+	}
+}
+
+func TestFp12CyclotomicSquareCompressed(t *testing.T) {
+	field := newFp12(nil)
+	gt := NewGT()
+	for i := 0; i < fuz; i++ {
+		a := gt.randCyclotomic()
+		u, v := field.new(), field.new()
+		field.cyclotomicSquare(u, a)
+		field.cyclotomicSquareCompressed(v, a)
+		w := []fe12{*v}
+		field.decompressKarabina(w)
+		if !u.equal(&w[0]) {
+			t.Fatal("bad compressed cyclotomic squaring")
+		}
+	}
+	// one is kept as one
+	w := []fe12{*field.one()}
+	field.cyclotomicSquareCompressed(&w[0], &w[0])
+	field.decompressKarabina(w)
+	if !w[0].equal(field.one()) {
+		t.Fatal("bad compressed cyclotomic squaring of one")
+	}
+}
+
+func TestFp12CyclotomicExpCompressed(t *testing.T) {
+	field := newFp12(nil)
+	gt := NewGT()
+	for i := 0; i < fuz; i++ {
+		a := gt.randCyclotomic()
+		r0, r1 := field.new(), field.new()
+		field.cyclotomicExp(r0, a, u)
+		field.cyclotomicExpCompressed(r1, a, u)
+		if !r0.equal(r1) {
+			t.Fatal("bad compressed exponentiation by u")
+		}
+		s := randScalar(q)
+		field.cyclotomicExp(r0, a, s)
+		field.cyclotomicExpCompressed(r1, a, s)
+		if !r0.equal(r1) {
+			t.Fatal("bad compressed exponentiation")
+		}
+		field.cyclotomicExpCompressed(r1, a, big.NewInt(0))
+		if !r1.equal(field.one()) {
+			t.Fatal("a^0 == 1")
+		}
+		field.cyclotomicExpCompressed(r1, a, big.NewInt(1))
+		if !r1.equal(a) {
+			t.Fatal("a^1 == a")
+		}
+	}
+}
+
+func BenchmarkFp12CyclotomicExp(t *testing.B) {
+	field := newFp12(nil)
+	a := NewGT().randCyclotomic()
+	c := field.new()
+	t.ResetTimer()
+	for i := 0; i < t.N; i++ {
+		field.cyclotomicExp(c, a, u)
+	}
+}
+
+func BenchmarkFp12CyclotomicExpCompressed(t *testing.B) {
+	field := newFp12(nil)
+	a := NewGT().randCyclotomic()
+	c := field.new()
+	t.ResetTimer()
+	for i := 0; i < t.N; i++ {
+		field.cyclotomicExpCompressed(c, a, u)
+	}
+}
+
+func BenchmarkFpLegendre(t *testing.B) {
+	a, _ := new(fe).rand(rand.Reader)
+	t.ResetTimer()
+	for i := 0; i < t.N; i++ {
+		legendre(a)
+	}
+}
+
+func BenchmarkFp2Sqrt(t *testing.B) {
+	field := newFp2()
+	a, _ := new(fe2).rand(rand.Reader)
+	field.square(a, a)
+	c := field.new()
+	t.ResetTimer()
+	for i := 0; i < t.N; i++ {
+		field.sqrt(c, a)
+	}
+}
@@ -0,0 +1,169 @@
+package bn254
+
+import (
+	"crypto/rand"
+	"errors"
+	"io"
+	"math/big"
+)
+
+//go:generate go run ./internal/fieldgen -modulus 0x30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f0000001 -type Fr -prefix fr -output fr_arithmetic
+
+// Fr is an element of the scalar field, integers modulo the group order q.
+// Elements are kept in Montgomery form.
+type Fr [4]uint64
+
+var frInp uint64 = 14042775128853446655
+
+var frModulus = Fr{0x43e1f593f0000001, 0x2833e84879b97091, 0xb85045b68181585d, 0x30644e72e131a029}
+
+// R mod q
+var frR1 = &Fr{0xac96341c4ffffffb, 0x36fc76959f60cd29, 0x666ea36f7879462e, 0x0e0a77c19a07df2f}
+
+// R^2 mod q
+var frR2 = &Fr{0x1bb8e645ae216da7, 0x53fe3ab1e35c59e3, 0x8c49833d53bb8085, 0x0216d0b17f4e44a5}
+
+// NewFr returns a new zero scalar field element.
+func NewFr() *Fr {
+	return &Fr{}
+}
+
+// FrFromBytes decodes 32 bytes big endian input. Returns error if input is not less than q.
+func FrFromBytes(in []byte) (*Fr, error) {
+	if len(in) != 32 {
+		return nil, errors.New("input string should be equal 32 bytes")
+	}
+	e := new(Fr)
+	(*fe)(e).setBytes(in)
+	if (*fe)(e).cmp((*fe)(&frModulus)) != -1 {
+		return nil, errors.New("must be less than modulus")
+	}
+	frMul(e, e, frR2)
+	return e, nil
+}
+
+// ToBytes encodes element into 32 bytes big endian.
+func (e *Fr) ToBytes() []byte {
+	t := new(Fr)
+	frMul(t, e, &Fr{1})
+	return (*fe)(t).bytes()
+}
+
+// Set copies e2 into e.
+func (e *Fr) Set(e2 *Fr) *Fr {
+	e[0] = e2[0]
+	e[1] = e2[1]
+	e[2] = e2[2]
+	e[3] = e2[3]
+	return e
+}
+
+// Zero sets e to zero.
+func (e *Fr) Zero() *Fr {
+	return e.Set(&Fr{})
+}
+
+// One sets e to one.
+func (e *Fr) One() *Fr {
+	return e.Set(frR1)
+}
+
+// SetUint64 sets e to n.
+func (e *Fr) SetUint64(n uint64) *Fr {
+	e.Set(&Fr{n})
+	frMul(e, e, frR2)
+	return e
+}
+
+// SetBig sets e to n mod q.
+func (e *Fr) SetBig(n *big.Int) *Fr {
+	(*fe)(e).setBig(new(big.Int).Mod(n, q))
+	frMul(e, e, frR2)
+	return e
+}
+
+// Big returns the integer value of e.
+func (e *Fr) Big() *big.Int {
+	t := new(Fr)
+	frMul(t, e, &Fr{1})
+	return (*fe)(t).big()
+}
+
+// Rand sets e to a uniformly random element using given source.
+func (e *Fr) Rand(r io.Reader) (*Fr, error) {
+	n, err := rand.Int(r, q)
+	if err != nil {
+		return nil, err
+	}
+	return e.SetBig(n), nil
+}
+
+// IsZero returns true if e is zero.
+func (e *Fr) IsZero() bool {
+	return (e[0] | e[1] | e[2] | e[3]) == 0
+}
+
+// IsOne returns true if e is one.
+func (e *Fr) IsOne() bool {
+	return e.Equal(frR1)
+}
+
+// Equal returns true if e and e2 are equal.
+func (e *Fr) Equal(e2 *Fr) bool {
+	return e[0] == e2[0] && e[1] == e2[1] && e[2] == e2[2] && e[3] == e2[3]
+}
+
+// Add sets e to a + b.
+func (e *Fr) Add(a, b *Fr) *Fr {
+	frAdd(e, a, b)
+	return e
+}
+
+// Double sets e to 2a.
+func (e *Fr) Double(a *Fr) *Fr {
+	frDouble(e, a)
+	return e
+}
+
+// Sub sets e to a - b.
+func (e *Fr) Sub(a, b *Fr) *Fr {
+	frSub(e, a, b)
+	return e
+}
+
+// Neg sets e to -a.
+func (e *Fr) Neg(a *Fr) *Fr {
+	frNeg(e, a)
+	return e
+}
+
+// Mul sets e to a * b.
+func (e *Fr) Mul(a, b *Fr) *Fr {
+	frMul(e, a, b)
+	return e
+}
+
+// Square sets e to a^2.
+func (e *Fr) Square(a *Fr) *Fr {
+	frSquare(e, a)
+	return e
+}
+
+// Exp sets e to a^s for a non negative exponent s.
+func (e *Fr) Exp(a *Fr, s *big.Int) *Fr {
+	z := new(Fr).One()
+	for i := s.BitLen() - 1; i >= 0; i-- {
+		frSquare(z, z)
+		if s.Bit(i) == 1 {
+			frMul(z, z, a)
+		}
+	}
+	return e.Set(z)
+}
+
+// Inverse sets e to a^(-1). Inverse of zero is zero.
+func (e *Fr) Inverse(a *Fr) *Fr {
+	return e.Exp(a, frModulusMinus2)
+}
+
+var frModulusMinus2 = new(big.Int).Sub(q, big.NewInt(2))
@@ -0,0 +1,1142 @@
+//go:build amd64 && !generic
+// +build amd64,!generic
+
+// Code generated by fieldgen -modulus 0x30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f0000001 -type Fr -prefix fr -output fr_arithmetic. DO NOT EDIT.
+
+#include "textflag.h"
+
+// func frAdd(c *[4]uint64, a *[4]uint64, b *[4]uint64)
+TEXT ·frAdd(SB), NOSPLIT, $0-24
+	MOVQ a+8(FP), DI
+	MOVQ b+16(FP), SI
+	XORQ AX, AX
+
+	MOVQ (DI), CX
+	ADDQ (SI), CX
+	MOVQ 8(DI), DX
+	ADCQ 8(SI), DX
+	MOVQ 16(DI), R8
+	ADCQ 16(SI), R8
+	MOVQ 24(DI), R9
+	ADCQ 24(SI), R9
+	ADCQ $0x00, AX
+
+	MOVQ CX, R10
+	SUBQ ·frModulus+0(SB), R10
+	MOVQ DX, R11
+	SBBQ ·frModulus+8(SB), R11
+	MOVQ R8, R12
+	SBBQ ·frModulus+16(SB), R12
+	MOVQ R9, R13
+	SBBQ ·frModulus+24(SB), R13
+	SBBQ $0x00, AX
+
+	MOVQ    c+0(FP), DI
+	CMOVQCC R10, CX
+	MOVQ    CX, (DI)
+	CMOVQCC R11, DX
+	MOVQ    DX, 8(DI)
+	CMOVQCC R12, R8
+	MOVQ    R8, 16(DI)
+	CMOVQCC R13, R9
+	MOVQ    R9, 24(DI)
+	RET
+
+// func frAddAssign(a *[4]uint64, b *[4]uint64)
+TEXT ·frAddAssign(SB), NOSPLIT, $0-16
+	MOVQ a+0(FP), DI
+	MOVQ b+8(FP), SI
+	XORQ AX, AX
+
+	MOVQ (DI), CX
+	ADDQ (SI), CX
+	MOVQ 8(DI), DX
+	ADCQ 8(SI), DX
+	MOVQ 16(DI), R8
+	ADCQ 16(SI), R8
+	MOVQ 24(DI), R9
+	ADCQ 24(SI), R9
+	ADCQ $0x00, AX
+
+	MOVQ CX, R10
+	SUBQ ·frModulus+0(SB), R10
+	MOVQ DX, R11
+	SBBQ ·frModulus+8(SB), R11
+	MOVQ R8, R12
+	SBBQ ·frModulus+16(SB), R12
+	MOVQ R9, R13
+	SBBQ ·frModulus+24(SB), R13
+	SBBQ $0x00, AX
+
+	CMOVQCC R10, CX
+	MOVQ    CX, (DI)
+	CMOVQCC R11, DX
+	MOVQ    DX, 8(DI)
+	CMOVQCC R12, R8
+	MOVQ    R8, 16(DI)
+	CMOVQCC R13, R9
+	MOVQ    R9, 24(DI)
+	RET
+
+// func frLaddAssign(a *[4]uint64, b *[4]uint64)
+TEXT ·frLaddAssign(SB), NOSPLIT, $0-16
+	MOVQ a+0(FP), DI
+	MOVQ b+8(FP), SI
+
+	MOVQ (DI), CX
+	ADDQ (SI), CX
+	MOVQ 8(DI), DX
+	ADCQ 8(SI), DX
+	MOVQ 16(DI), R8
+	ADCQ 16(SI), R8
+	MOVQ 24(DI), R9
+	ADCQ 24(SI), R9
+
+	MOVQ CX, (DI)
+	MOVQ DX, 8(DI)
+	MOVQ R8, 16(DI)
+	MOVQ R9, 24(DI)
+	RET
+
+// func frDouble(c *[4]uint64, a *[4]uint64)
+TEXT ·frDouble(SB), NOSPLIT, $0-16
+	MOVQ a+8(FP), DI
+	XORQ AX, AX
+	MOVQ (DI), CX
+	ADDQ CX, CX
+	MOVQ 8(DI), DX
+	ADCQ DX, DX
+	MOVQ 16(DI), SI
+	ADCQ SI, SI
+	MOVQ 24(DI), R8
+	ADCQ R8, R8
+	ADCQ $0x00, AX
+
+	MOVQ CX, R9
+	SUBQ ·frModulus+0(SB), R9
+	MOVQ DX, R10
+	SBBQ ·frModulus+8(SB), R10
+	MOVQ SI, R11
+	SBBQ ·frModulus+16(SB), R11
+	MOVQ R8, R12
+	SBBQ ·frModulus+24(SB), R12
+	SBBQ $0x00, AX
+
+	MOVQ    c+0(FP), DI
+	CMOVQCC R9, CX
+	MOVQ    CX, (DI)
+	CMOVQCC R10, DX
+	MOVQ    DX, 8(DI)
+	CMOVQCC R11, SI
+	MOVQ    SI, 16(DI)
+	CMOVQCC R12, R8
+	MOVQ    R8, 24(DI)
+	RET
+
+// func frDoubleAssign(a *[4]uint64)
+TEXT ·frDoubleAssign(SB), NOSPLIT, $0-8
+	MOVQ a+0(FP), DI
+	XORQ AX, AX
+	MOVQ (DI), CX
+	ADDQ CX, CX
+	MOVQ 8(DI), DX
+	ADCQ DX, DX
+	MOVQ 16(DI), SI
+	ADCQ SI, SI
+	MOVQ 24(DI), R8
+	ADCQ R8, R8
+	ADCQ $0x00, AX
+
+	MOVQ CX, R9
+	SUBQ ·frModulus+0(SB), R9
+	MOVQ DX, R10
+	SBBQ ·frModulus+8(SB), R10
+	MOVQ SI, R11
+	SBBQ ·frModulus+16(SB), R11
+	MOVQ R8, R12
+	SBBQ ·frModulus+24(SB), R12
+	SBBQ $0x00, AX
+
+	CMOVQCC R9, CX
+	MOVQ    CX, (DI)
+	CMOVQCC R10, DX
+	MOVQ    DX, 8(DI)
+	CMOVQCC R11, SI
+	MOVQ    SI, 16(DI)
+	CMOVQCC R12, R8
+	MOVQ    R8, 24(DI)
+	RET
+
+// func frSub(c *[4]uint64, a *[4]uint64, b *[4]uint64)
+TEXT ·frSub(SB), NOSPLIT, $0-24
+	MOVQ a+8(FP), DI
+	MOVQ b+16(FP), SI
+	XORQ AX, AX
+	MOVQ (DI), CX
+	SUBQ (SI), CX
+	MOVQ 8(DI), DX
+	SBBQ 8(SI), DX
+	MOVQ 16(DI), R8
+	SBBQ 16(SI), R8
+	MOVQ 24(DI), R9
+	SBBQ 24(SI), R9
+
+	MOVQ    ·frModulus+0(SB), SI
+	CMOVQCC AX, SI
+	MOVQ    ·frModulus+8(SB), R10
+	CMOVQCC AX, R10
+	MOVQ    ·frModulus+16(SB), R11
+	CMOVQCC AX, R11
+	MOVQ    ·frModulus+24(SB), R12
+	CMOVQCC AX, R12
+
+	MOVQ c+0(FP), DI
+	ADDQ SI, CX
+	MOVQ CX, (DI)
+	ADCQ R10, DX
+	MOVQ DX, 8(DI)
+	ADCQ R11, R8
+	MOVQ R8, 16(DI)
+	ADCQ R12, R9
+	MOVQ R9, 24(DI)
+	RET
+
+// func frSubAssign(a *[4]uint64, b *[4]uint64)
+TEXT ·frSubAssign(SB), NOSPLIT, $0-16
+	MOVQ a+0(FP), DI
+	MOVQ b+8(FP), SI
+	XORQ AX, AX
+	MOVQ (DI), CX
+	SUBQ (SI), CX
+	MOVQ 8(DI), DX
+	SBBQ 8(SI), DX
+	MOVQ 16(DI), R8
+	SBBQ 16(SI), R8
+	MOVQ 24(DI), R9
+	SBBQ 24(SI), R9
+
+	MOVQ    ·frModulus+0(SB), SI
+	CMOVQCC AX, SI
+	MOVQ    ·frModulus+8(SB), R10
+	CMOVQCC AX, R10
+	MOVQ    ·frModulus+16(SB), R11
+	CMOVQCC AX, R11
+	MOVQ    ·frModulus+24(SB), R12
+	CMOVQCC AX, R12
+
+	ADDQ SI, CX
+	MOVQ CX, (DI)
+	ADCQ R10, DX
+	MOVQ DX, 8(DI)
+	ADCQ R11, R8
+	MOVQ R8, 16(DI)
+	ADCQ R12, R9
+	MOVQ R9, 24(DI)
+	RET
+
+// func frLsubAssign(a *[4]uint64, b *[4]uint64) uint64
+TEXT ·frLsubAssign(SB), NOSPLIT, $0-24
+	MOVQ a+0(FP), DI
+	MOVQ b+8(FP), SI
+	XORQ AX, AX
+
+	MOVQ (DI), CX
+	SUBQ (SI), CX
+	MOVQ 8(DI), DX
+	SBBQ 8(SI), DX
+	MOVQ 16(DI), R8
+	SBBQ 16(SI), R8
+	MOVQ 24(DI), R9
+	SBBQ 24(SI), R9
+	ADCQ $0x00, AX
+
+	MOVQ CX, (DI)
+	MOVQ DX, 8(DI)
+	MOVQ R8, 16(DI)
+	MOVQ R9, 24(DI)
+	MOVQ AX, ret+16(FP)
+	RET
+
+// func frNeg(c *[4]uint64, a *[4]uint64)
+TEXT ·frNeg(SB), NOSPLIT, $0-16
+	MOVQ a+8(FP), DI
+	XORQ R9, R9
+
+	MOVQ ·frModulus+0(SB), CX
+	SUBQ (DI), CX
+	MOVQ ·frModulus+8(SB), DX
+	SBBQ 8(DI), DX
+	MOVQ ·frModulus+16(SB), SI
+	SBBQ 16(DI), SI
+	MOVQ ·frModulus+24(SB), R8
+	SBBQ 24(DI), R8
+
+	MOVQ    (DI), AX
+	ORQ     8(DI), AX
+	ORQ     16(DI), AX
+	ORQ     24(DI), AX
+	CMOVQEQ R9, CX
+	CMOVQEQ R9, DX
+	CMOVQEQ R9, SI
+	CMOVQEQ R9, R8
+
+	MOVQ c+0(FP), DI
+	MOVQ CX, (DI)
+	MOVQ DX, 8(DI)
+	MOVQ SI, 16(DI)
+	MOVQ R8, 24(DI)
+	RET
+
+// func frMulADX(c *[4]uint64, a *[4]uint64, b *[4]uint64)
+TEXT ·frMulADX(SB), NOSPLIT, $0-24
+	MOVQ a+8(FP), DI
+	MOVQ b+16(FP), SI
+	XORQ AX, AX
+
+	// | a0 @ DX
+	MOVQ  (DI), DX
+	MULXQ (SI), CX, R8
+	MULXQ 8(SI), AX, R9
+	ADCXQ AX, R8
+	MULXQ 16(SI), AX, R10
+	ADCXQ AX, R9
+	MULXQ 24(SI), AX, R11
+	ADCXQ AX, R10
+	ADCQ  $0x00, R11
+
+	// | a1 @ DX
+	MOVQ  8(DI), DX
+	XORQ  R12, R12
+	MULXQ (SI), AX, BX
+	ADOXQ AX, R8
+	ADCXQ BX, R9
+	MULXQ 8(SI), AX, BX
+	ADOXQ AX, R9
+	ADCXQ BX, R10
+	MULXQ 16(SI), AX, BX
+	ADOXQ AX, R10
+	ADCXQ BX, R11
+	MULXQ 24(SI), AX, BX
+	ADOXQ AX, R11
+	ADOXQ R12, R12
+	ADCXQ BX, R12
+
+	// | a2 @ DX
+	MOVQ  16(DI), DX
+	XORQ  R13, R13
+	MULXQ (SI), AX, BX
+	ADOXQ AX, R9
+	ADCXQ BX, R10
+	MULXQ 8(SI), AX, BX
+	ADOXQ AX, R10
+	ADCXQ BX, R11
+	MULXQ 16(SI), AX, BX
+	ADOXQ AX, R11
+	ADCXQ BX, R12
+	MULXQ 24(SI), AX, BX
+	ADOXQ AX, R12
+	ADOXQ R13, R13
+	ADCXQ BX, R13
+
+	// | a3 @ DX
+	MOVQ  24(DI), DX
+	XORQ  DI, DI
+	MULXQ (SI), AX, BX
+	ADOXQ AX, R10
+	ADCXQ BX, R11
+	MULXQ 8(SI), AX, BX
+	ADOXQ AX, R11
+	ADCXQ BX, R12
+	MULXQ 16(SI), AX, BX
+	ADOXQ AX, R12
+	ADCXQ BX, R13
+	MULXQ 24(SI), AX, BX
+	ADOXQ AX, R13
+	ADOXQ DI, DI
+	ADCXQ BX, DI
+
+	// | montgomery reduction
+	// | u0 = w0 * inp
+	XORQ  AX, AX
+	MOVQ  CX, DX
+	MULXQ ·frInp+0(SB), DX, BX
+	MULXQ ·frModulus+0(SB), AX, BX
+	ADOXQ AX, CX
+	ADCXQ BX, R8
+	MULXQ ·frModulus+8(SB), AX, BX
+	ADOXQ AX, R8
+	ADCXQ BX, R9
+	MULXQ ·frModulus+16(SB), AX, BX
+	ADOXQ AX, R9
+	ADCXQ BX, R10
+	MULXQ ·frModulus+24(SB), AX, BX
+	ADOXQ AX, R10
+	ADCXQ BX, R11
+	ADOXQ CX, R11
+	ADCXQ CX, CX
+	MOVQ  $0x00, AX
+	ADOXQ AX, CX
+
+	// | u1 = w1 * inp
+	XORQ  AX, AX
+	MOVQ  R8, DX
+	MULXQ ·frInp+0(SB), DX, BX
+	MULXQ ·frModulus+0(SB), AX, BX
+	ADOXQ AX, R8
+	ADCXQ BX, R9
+	MULXQ ·frModulus+8(SB), AX, BX
+	ADOXQ AX, R9
+	ADCXQ BX, R10
+	MULXQ ·frModulus+16(SB), AX, BX
+	ADOXQ AX, R10
+	ADCXQ BX, R11
+	MULXQ ·frModulus+24(SB), AX, BX
+	ADOXQ AX, R11
+	ADCXQ BX, R12
+	ADOXQ CX, R12
+	ADCXQ R8, R8
+	MOVQ  $0x00, AX
+	ADOXQ AX, R8
+
+	// | u2 = w2 * inp
+	XORQ  AX, AX
+	MOVQ  R9, DX
+	MULXQ ·frInp+0(SB), DX, BX
+	MULXQ ·frModulus+0(SB), AX, BX
+	ADOXQ AX, R9
+	ADCXQ BX, R10
+	MULXQ ·frModulus+8(SB), AX, BX
+	ADOXQ AX, R10
+	ADCXQ BX, R11
+	MULXQ ·frModulus+16(SB), AX, BX
+	ADOXQ AX, R11
+	ADCXQ BX, R12
+	MULXQ ·frModulus+24(SB), AX, BX
+	ADOXQ AX, R12
+	ADCXQ BX, R13
+	ADOXQ R8, R13
+	ADCXQ R9, R9
+	MOVQ  $0x00, AX
+	ADOXQ AX, R9
+
+	// | u3 = w3 * inp
+	XORQ  AX, AX
+	MOVQ  R10, DX
+	MULXQ ·frInp+0(SB), DX, BX
+	MULXQ ·frModulus+0(SB), AX, BX
+	ADOXQ AX, R10
+	ADCXQ BX, R11
+	MULXQ ·frModulus+8(SB), AX, BX
+	ADOXQ AX, R11
+	ADCXQ BX, R12
+	MULXQ ·frModulus+16(SB), AX, BX
+	ADOXQ AX, R12
+	ADCXQ BX, R13
+	MULXQ ·frModulus+24(SB), AX, BX
+	ADOXQ AX, R13
+	ADCXQ BX, DI
+	ADOXQ R9, DI
+	ADCXQ R10, R10
+	MOVQ  $0x00, AX
+	ADOXQ AX, R10
+
+	// | modular reduction
+	MOVQ R11, CX
+	SUBQ ·frModulus+0(SB), CX
+	MOVQ R12, AX
+	SBBQ ·frModulus+8(SB), AX
+	MOVQ R13, BX
+	SBBQ ·frModulus+16(SB), BX
+	MOVQ DI, SI
+	SBBQ ·frModulus+24(SB), SI
+	SBBQ $0x00, R10
+
+	MOVQ    c+0(FP), R10
+	CMOVQCC CX, R11
+	MOVQ    R11, (R10)
+	CMOVQCC AX, R12
+	MOVQ    R12, 8(R10)
+	CMOVQCC BX, R13
+	MOVQ    R13, 16(R10)
+	CMOVQCC SI, DI
+	MOVQ    DI, 24(R10)
+	RET
+
+// func frSquareADX(c *[4]uint64, a *[4]uint64)
+TEXT ·frSquareADX(SB), NOSPLIT, $0-16
+	MOVQ a+8(FP), SI
+
+	// | cross products
+	MOVQ  (SI), DX
+	MULXQ 8(SI), R8, R9
+	MULXQ 16(SI), AX, R10
+	ADDQ  AX, R9
+	MULXQ 24(SI), AX, R11
+	ADCQ  AX, R10
+	ADCQ  $0x00, R11
+	MOVQ  8(SI), DX
+	XORQ  R12, R12
+	MULXQ 16(SI), AX, BX
+	ADOXQ AX, R10
+	ADCXQ BX, R11
+	MULXQ 24(SI), AX, BX
+	ADOXQ AX, R11
+	ADCXQ BX, R12
+	MOVQ  $0x00, AX
+	ADOXQ AX, R12
+	MOVQ  16(SI), DX
+	MULXQ 24(SI), AX, R13
+	ADDQ  AX, R12
+	ADCQ  $0x00, R13
+
+	// | double cross products
+	XORQ DI, DI
+	ADDQ R8, R8
+	ADCQ R9, R9
+	ADCQ R10, R10
+	ADCQ R11, R11
+	ADCQ R12, R12
+	ADCQ R13, R13
+	ADCQ $0x00, DI
+
+	// | diagonal
+	MOVQ  (SI), DX
+	MULXQ DX, CX, AX
+	ADDQ  AX, R8
+	MOVQ  8(SI), DX
+	MULXQ DX, AX, BX
+	ADCQ  AX, R9
+	ADCQ  BX, R10
+	MOVQ  16(SI), DX
+	MULXQ DX, AX, BX
+	ADCQ  AX, R11
+	ADCQ  BX, R12
+	MOVQ  24(SI), DX
+	MULXQ DX, AX, BX
+	ADCQ  AX, R13
+	ADCQ  BX, DI
+
+	// | montgomery reduction
+	// | u0 = w0 * inp
+	XORQ  AX, AX
+	MOVQ  CX, DX
+	MULXQ ·frInp+0(SB), DX, BX
+	MULXQ ·frModulus+0(SB), AX, BX
+	ADOXQ AX, CX
+	ADCXQ BX, R8
+	MULXQ ·frModulus+8(SB), AX, BX
+	ADOXQ AX, R8
+	ADCXQ BX, R9
+	MULXQ ·frModulus+16(SB), AX, BX
+	ADOXQ AX, R9
+	ADCXQ BX, R10
+	MULXQ ·frModulus+24(SB), AX, BX
+	ADOXQ AX, R10
+	ADCXQ BX, R11
+	ADOXQ CX, R11
+	ADCXQ CX, CX
+	MOVQ  $0x00, AX
+	ADOXQ AX, CX
+
+	// | u1 = w1 * inp
+	XORQ  AX, AX
+	MOVQ  R8, DX
+	MULXQ ·frInp+0(SB), DX, BX
+	MULXQ ·frModulus+0(SB), AX, BX
+	ADOXQ AX, R8
+	ADCXQ BX, R9
+	MULXQ ·frModulus+8(SB), AX, BX
+	ADOXQ AX, R9
+	ADCXQ BX, R10
+	MULXQ ·frModulus+16(SB), AX, BX
+	ADOXQ AX, R10
+	ADCXQ BX, R11
+	MULXQ ·frModulus+24(SB), AX, BX
+	ADOXQ AX, R11
+	ADCXQ BX, R12
+	ADOXQ CX, R12
+	ADCXQ R8, R8
+	MOVQ  $0x00, AX
+	ADOXQ AX, R8
+
+	// | u2 = w2 * inp
+	XORQ  AX, AX
+	MOVQ  R9, DX
+	MULXQ ·frInp+0(SB), DX, BX
+	MULXQ ·frModulus+0(SB), AX, BX
+	ADOXQ AX, R9
+	ADCXQ BX, R10
+	MULXQ ·frModulus+8(SB), AX, BX
+	ADOXQ AX, R10
+	ADCXQ BX, R11
+	MULXQ ·frModulus+16(SB), AX, BX
+	ADOXQ AX, R11
+	ADCXQ BX, R12
+	MULXQ ·frModulus+24(SB), AX, BX
+	ADOXQ AX, R12
+	ADCXQ BX, R13
+	ADOXQ R8, R13
+	ADCXQ R9, R9
+	MOVQ  $0x00, AX
+	ADOXQ AX, R9
+
+	// | u3 = w3 * inp
+	XORQ  AX, AX
+	MOVQ  R10, DX
+	MULXQ ·frInp+0(SB), DX, BX
+	MULXQ ·frModulus+0(SB), AX, BX
+	ADOXQ AX, R10
+	ADCXQ BX, R11
+	MULXQ ·frModulus+8(SB), AX, BX
+	ADOXQ AX, R11
+	ADCXQ BX, R12
+	MULXQ ·frModulus+16(SB), AX, BX
+	ADOXQ AX, R12
+	ADCXQ BX, R13
+	MULXQ ·frModulus+24(SB), AX, BX
+	ADOXQ AX, R13
+	ADCXQ BX, DI
+	ADOXQ R9, DI
+	ADCXQ R10, R10
+	MOVQ  $0x00, AX
+	ADOXQ AX, R10
+
+	// | modular reduction
+	MOVQ R11, CX
+	SUBQ ·frModulus+0(SB), CX
+	MOVQ R12, AX
+	SBBQ ·frModulus+8(SB), AX
+	MOVQ R13, BX
+	SBBQ ·frModulus+16(SB), BX
+	MOVQ DI, SI
+	SBBQ ·frModulus+24(SB), SI
+	SBBQ $0x00, R10
+
+	MOVQ    c+0(FP), R10
+	CMOVQCC CX, R11
+	MOVQ    R11, (R10)
+	CMOVQCC AX, R12
+	MOVQ    R12, 8(R10)
+	CMOVQCC BX, R13
+	MOVQ    R13, 16(R10)
+	CMOVQCC SI, DI
+	MOVQ    DI, 24(R10)
+	RET
+
+// func frMulNoADX(c *[4]uint64, a *[4]uint64, b *[4]uint64)
+TEXT ·frMulNoADX(SB), NOSPLIT, $0-24
+	MOVQ a+8(FP), DI
+	MOVQ b+16(FP), SI
+	MOVQ $0x00, R10
+	MOVQ $0x00, R11
+	MOVQ $0x00, R12
+	MOVQ $0x00, R13
+	MOVQ $0x00, R14
+
+	// | a0 @ CX
+	MOVQ (DI), CX
+
+	// | a0 * b0
+	MOVQ (SI), AX
+	MULQ CX
+	MOVQ AX, R8
+	MOVQ DX, R9
+
+	// | a0 * b1
+	MOVQ 8(SI), AX
+	MULQ CX
+	ADDQ AX, R9
+	ADCQ DX, R10
+
+	// | a0 * b2
+	MOVQ 16(SI), AX
+	MULQ CX
+	ADDQ AX, R10
+	ADCQ DX, R11
+
+	// | a0 * b3
+	MOVQ 24(SI), AX
+	MULQ CX
+	ADDQ AX, R11
+	ADCQ DX, R12
+
+	// | a1 @ CX
+	MOVQ 8(DI), CX
+	MOVQ $0x00, BX
+
+	// | a1 * b0
+	MOVQ (SI), AX
+	MULQ CX
+	ADDQ AX, R9
+	ADCQ DX, R10
+	ADCQ $0x00, R11
+	ADCQ $0x00, BX
+
+	// | a1 * b1
+	MOVQ 8(SI), AX
+	MULQ CX
+	ADDQ AX, R10
+	ADCQ DX, R11
+	ADCQ BX, R12
+	MOVQ $0x00, BX
+	ADCQ $0x00, BX
+
+	// | a1 * b2
+	MOVQ 16(SI), AX
+	MULQ CX
+	ADDQ AX, R11
+	ADCQ DX, R12
+	ADCQ BX, R13
+
+	// | a1 * b3
+	MOVQ 24(SI), AX
+	MULQ CX
+	ADDQ AX, R12
+	ADCQ DX, R13
+
+	// | a2 @ CX
+	MOVQ 16(DI), CX
+	MOVQ $0x00, BX
+
+	// | a2 * b0
+	MOVQ (SI), AX
+	MULQ CX
+	ADDQ AX, R10
+	ADCQ DX, R11
+	ADCQ $0x00, R12
+	ADCQ $0x00, BX
+
+	// | a2 * b1
+	MOVQ 8(SI), AX
+	MULQ CX
+	ADDQ AX, R11
+	ADCQ DX, R12
+	ADCQ BX, R13
+	MOVQ $0x00, BX
+	ADCQ $0x00, BX
+
+	// | a2 * b2
+	MOVQ 16(SI), AX
+	MULQ CX
+	ADDQ AX, R12
+	ADCQ DX, R13
+	ADCQ BX, R14
+
+	// | a2 * b3
+	MOVQ 24(SI), AX
+	MULQ CX
+	ADDQ AX, R13
+	ADCQ DX, R14
+
+	// | a3 @ CX
+	MOVQ 24(DI), CX
+	MOVQ $0x00, BX
+
+	// | a3 * b0
+	MOVQ (SI), AX
+	MULQ CX
+	ADDQ AX, R11
+	ADCQ DX, R12
+	ADCQ $0x00, R13
+	ADCQ $0x00, BX
+
+	// | a3 * b1
+	MOVQ 8(SI), AX
+	MULQ CX
+	ADDQ AX, R12
+	ADCQ DX, R13
+	ADCQ BX, R14
+	MOVQ $0x00, BX
+	ADCQ $0x00, BX
+
+	// | a3 * b2
+	MOVQ 16(SI), AX
+	MULQ CX
+	ADDQ AX, R13
+	ADCQ DX, R14
+	ADCQ $0x00, BX
+
+	// | a3 * b3
+	MOVQ 24(SI), AX
+	MULQ CX
+	ADDQ AX, R14
+	ADCQ DX, BX
+
+	// | montgomery reduction
+	// | u0 = w0 * inp
+	MOVQ R8, AX
+	MULQ ·frInp+0(SB)
+	MOVQ AX, DI
+	MOVQ $0x00, CX
+
+	MOVQ ·frModulus+0(SB), AX
+	MULQ DI
+	ADDQ AX, R8
+	ADCQ DX, CX
+	MOVQ ·frModulus+8(SB), AX
+	MULQ DI
+	ADDQ AX, R9
+	ADCQ $0x00, DX
+	ADDQ CX, R9
+	MOVQ $0x00, CX
+	ADCQ DX, CX
+	MOVQ ·frModulus+16(SB), AX
+	MULQ DI
+	ADDQ AX, R10
+	ADCQ $0x00, DX
+	ADDQ CX, R10
+	MOVQ $0x00, CX
+	ADCQ DX, CX
+	MOVQ ·frModulus+24(SB), AX
+	MULQ DI
+	ADDQ AX, R11
+	ADCQ $0x00, DX
+	ADDQ CX, R11
+	ADCQ DX, R12
+	ADCQ $0x00, R8
+
+	// | u1 = w1 * inp
+	MOVQ R9, AX
+	MULQ ·frInp+0(SB)
+	MOVQ AX, DI
+	MOVQ $0x00, CX
+
+	MOVQ ·frModulus+0(SB), AX
+	MULQ DI
+	ADDQ AX, R9
+	ADCQ DX, CX
+	MOVQ ·frModulus+8(SB), AX
+	MULQ DI
+	ADDQ AX, R10
+	ADCQ $0x00, DX
+	ADDQ CX, R10
+	MOVQ $0x00, CX
+	ADCQ DX, CX
+	MOVQ ·frModulus+16(SB), AX
+	MULQ DI
+	ADDQ AX, R11
+	ADCQ $0x00, DX
+	ADDQ CX, R11
+	MOVQ $0x00, CX
+	ADCQ DX, CX
+	MOVQ ·frModulus+24(SB), AX
+	MULQ DI
+	ADDQ AX, R12
+	ADCQ DX, R8
+	ADDQ CX, R12
+	ADCQ R8, R13
+	MOVQ $0x00, R8
+	ADCQ $0x00, R8
+
+	// | u2 = w2 * inp
+	MOVQ R10, AX
+	MULQ ·frInp+0(SB)
+	MOVQ AX, DI
+	MOVQ $0x00, CX
+
+	MOVQ ·frModulus+0(SB), AX
+	MULQ DI
+	ADDQ AX, R10
+	ADCQ DX, CX
+	MOVQ ·frModulus+8(SB), AX
+	MULQ DI
+	ADDQ AX, R11
+	ADCQ $0x00, DX
+	ADDQ CX, R11
+	MOVQ $0x00, CX
+	ADCQ DX, CX
+	MOVQ ·frModulus+16(SB), AX
+	MULQ DI
+	ADDQ AX, R12
+	ADCQ $0x00, DX
+	ADDQ CX, R12
+	MOVQ $0x00, CX
+	ADCQ DX, CX
+	MOVQ ·frModulus+24(SB), AX
+	MULQ DI
+	ADDQ AX, R13
+	ADCQ DX, R8
+	ADDQ CX, R13
+	ADCQ R8, R14
+	MOVQ $0x00, R8
+	ADCQ $0x00, R8
+
+	// | u3 = w3 * inp
+	MOVQ R11, AX
+	MULQ ·frInp+0(SB)
+	MOVQ AX, DI
+	MOVQ $0x00, CX
+
+	MOVQ ·frModulus+0(SB), AX
+	MULQ DI
+	ADDQ AX, R11
+	ADCQ DX, CX
+	MOVQ ·frModulus+8(SB), AX
+	MULQ DI
+	ADDQ AX, R12
+	ADCQ $0x00, DX
+	ADDQ CX, R12
+	MOVQ $0x00, CX
+	ADCQ DX, CX
+	MOVQ ·frModulus+16(SB), AX
+	MULQ DI
+	ADDQ AX, R13
+	ADCQ $0x00, DX
+	ADDQ CX, R13
+	MOVQ $0x00, CX
+	ADCQ DX, CX
+	MOVQ ·frModulus+24(SB), AX
+	MULQ DI
+	ADDQ AX, R14
+	ADCQ DX, R8
+	ADDQ CX, R14
+	ADCQ R8, BX
+	MOVQ $0x00, R8
+	ADCQ $0x00, R8
+
+	// | modular reduction
+	MOVQ R12, SI
+	SUBQ ·frModulus+0(SB), SI
+	MOVQ R13, R9
+	SBBQ ·frModulus+8(SB), R9
+	MOVQ R14, R10
+	SBBQ ·frModulus+16(SB), R10
+	MOVQ BX, R11
+	SBBQ ·frModulus+24(SB), R11
+	SBBQ $0x00, R8
+
+	MOVQ    c+0(FP), R8
+	CMOVQCC SI, R12
+	MOVQ    R12, (R8)
+	CMOVQCC R9, R13
+	MOVQ    R13, 8(R8)
+	CMOVQCC R10, R14
+	MOVQ    R14, 16(R8)
+	CMOVQCC R11, BX
+	MOVQ    BX, 24(R8)
+	RET
+
+// func frSquareNoADX(c *[4]uint64, a *[4]uint64)
+TEXT ·frSquareNoADX(SB), NOSPLIT, $0-16
+	MOVQ a+8(FP), DI
+
+	// | cross products
+	MOVQ (DI), CX
+	MOVQ 8(DI), AX
+	MULQ CX
+	MOVQ AX, R9
+	MOVQ DX, R10
+	MOVQ 16(DI), AX
+	MULQ CX
+	ADDQ AX, R10
+	ADCQ $0x00, DX
+	MOVQ DX, R11
+	MOVQ 24(DI), AX
+	MULQ CX
+	ADDQ AX, R11
+	ADCQ $0x00, DX
+	MOVQ DX, R12
+	MOVQ 8(DI), CX
+	MOVQ 16(DI), AX
+	MULQ CX
+	ADDQ AX, R11
+	ADCQ DX, R12
+	MOVQ $0x00, R13
+	ADCQ $0x00, R13
+	MOVQ 24(DI), AX
+	MULQ CX
+	ADDQ AX, R12
+	ADCQ DX, R13
+	MOVQ 16(DI), CX
+	MOVQ 24(DI), AX
+	MULQ CX
+	ADDQ AX, R13
+	ADCQ $0x00, DX
+	MOVQ DX, R14
+
+	// | double cross products
+	MOVQ $0x00, BX
+	ADDQ R9, R9
+	ADCQ R10, R10
+	ADCQ R11, R11
+	ADCQ R12, R12
+	ADCQ R13, R13
+	ADCQ R14, R14
+	ADCQ $0x00, BX
+
+	// | diagonal
+	MOVQ (DI), AX
+	MULQ AX
+	MOVQ AX, R8
+	MOVQ DX, CX
+	MOVQ 8(DI), AX
+	MULQ AX
+	ADDQ CX, R9
+	ADCQ AX, R10
+	ADCQ $0x00, DX
+	MOVQ DX, CX
+	MOVQ 16(DI), AX
+	MULQ AX
+	ADDQ CX, R11
+	ADCQ AX, R12
+	ADCQ $0x00, DX
+	MOVQ DX, CX
+	MOVQ 24(DI), AX
+	MULQ AX
+	ADDQ CX, R13
+	ADCQ AX, R14
+	ADCQ DX, BX
+
+	// | montgomery reduction
+	// | u0 = w0 * inp
+	MOVQ R8, AX
+	MULQ ·frInp+0(SB)
+	MOVQ AX, DI
+	MOVQ $0x00, CX
+
+	MOVQ ·frModulus+0(SB), AX
+	MULQ DI
+	ADDQ AX, R8
+	ADCQ DX, CX
+	MOVQ ·frModulus+8(SB), AX
+	MULQ DI
+	ADDQ AX, R9
+	ADCQ $0x00, DX
+	ADDQ CX, R9
+	MOVQ $0x00, CX
+	ADCQ DX, CX
+	MOVQ ·frModulus+16(SB), AX
+	MULQ DI
+	ADDQ AX, R10
+	ADCQ $0x00, DX
+	ADDQ CX, R10
+	MOVQ $0x00, CX
+	ADCQ DX, CX
+	MOVQ ·frModulus+24(SB), AX
+	MULQ DI
+	ADDQ AX, R11
+	ADCQ $0x00, DX
+	ADDQ CX, R11
+	ADCQ DX, R12
+	ADCQ $0x00, R8
+
+	// | u1 = w1 * inp
+	MOVQ R9, AX
+	MULQ ·frInp+0(SB)
+	MOVQ AX, DI
+	MOVQ $0x00, CX
+
+	MOVQ ·frModulus+0(SB), AX
+	MULQ DI
+	ADDQ AX, R9
+	ADCQ DX, CX
+	MOVQ ·frModulus+8(SB), AX
+	MULQ DI
+	ADDQ AX, R10
+	ADCQ $0x00, DX
+	ADDQ CX, R10
+	MOVQ $0x00, CX
+	ADCQ DX, CX
+	MOVQ ·frModulus+16(SB), AX
+	MULQ DI
+	ADDQ AX, R11
+	ADCQ $0x00, DX
+	ADDQ CX, R11
+	MOVQ $0x00, CX
+	ADCQ DX, CX
+	MOVQ ·frModulus+24(SB), AX
+	MULQ DI
+	ADDQ AX, R12
+	ADCQ DX, R8
+	ADDQ CX, R12
+	ADCQ R8, R13
+	MOVQ $0x00, R8
+	ADCQ $0x00, R8
+
+	// | u2 = w2 * inp
+	MOVQ R10, AX
+	MULQ ·frInp+0(SB)
+	MOVQ AX, DI
+	MOVQ $0x00, CX
+
+	MOVQ ·frModulus+0(SB), AX
+	MULQ DI
+	ADDQ AX, R10
+	ADCQ DX, CX
+	MOVQ ·frModulus+8(SB), AX
+	MULQ DI
+	ADDQ AX, R11
+	ADCQ $0x00, DX
+	ADDQ CX, R11
+	MOVQ $0x00, CX
+	ADCQ DX, CX
+	MOVQ ·frModulus+16(SB), AX
+	MULQ DI
+	ADDQ AX, R12
+	ADCQ $0x00, DX
+	ADDQ CX, R12
+	MOVQ $0x00, CX
+	ADCQ DX, CX
+	MOVQ ·frModulus+24(SB), AX
+	MULQ DI
+	ADDQ AX, R13
+	ADCQ DX, R8
+	ADDQ CX, R13
+	ADCQ R8, R14
+	MOVQ $0x00, R8
+	ADCQ $0x00, R8
+
+	// | u3 = w3 * inp
+	MOVQ R11, AX
+	MULQ ·frInp+0(SB)
+	MOVQ AX, DI
+	MOVQ $0x00, CX
+
+	MOVQ ·frModulus+0(SB), AX
+	MULQ DI
+	ADDQ AX, R11
+	ADCQ DX, CX
+	MOVQ ·frModulus+8(SB), AX
+	MULQ DI
+	ADDQ AX, R12
+	ADCQ $0x00, DX
+	ADDQ CX, R12
+	MOVQ $0x00, CX
+	ADCQ DX, CX
+	MOVQ ·frModulus+16(SB), AX
+	MULQ DI
+	ADDQ AX, R13
+	ADCQ $0x00, DX
+	ADDQ CX, R13
+	MOVQ $0x00, CX
+	ADCQ DX, CX
+	MOVQ ·frModulus+24(SB), AX
+	MULQ DI
+	ADDQ AX, R14
+	ADCQ DX, R8
+	ADDQ CX, R14
+	ADCQ R8, BX
+	MOVQ $0x00, R8
+	ADCQ $0x00, R8
+
+	// | modular reduction
+	MOVQ R12, SI
+	SUBQ ·frModulus+0(SB), SI
+	MOVQ R13, R9
+	SBBQ ·frModulus+8(SB), R9
+	MOVQ R14, R10
+	SBBQ ·frModulus+16(SB), R10
+	MOVQ BX, R11
+	SBBQ ·frModulus+24(SB), R11
+	SBBQ $0x00, R8
+
+	MOVQ    c+0(FP), R8
+	CMOVQCC SI, R12
+	MOVQ    R12, (R8)
+	CMOVQCC R9, R13
+	MOVQ    R13, 8(R8)
+	CMOVQCC R10, R14
+	MOVQ    R14, 16(R8)
+	CMOVQCC R11, BX
+	MOVQ    BX, 24(R8)
+	RET
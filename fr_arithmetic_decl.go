@@ -0,0 +1,59 @@
+//go:build amd64 && !generic
+// +build amd64,!generic
+
+// Code generated by fieldgen -modulus 0x30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f0000001 -type Fr -prefix fr -output fr_arithmetic. DO NOT EDIT.
+
+package bn254
+
+import "golang.org/x/sys/cpu"
+
+// ADX and BMI2 variants are the defaults, init falls back to the others where they are not available.
+func init() {
+	if !cpu.X86.HasADX || !cpu.X86.HasBMI2 {
+		frMul = frMulNoADX
+		frSquare = frSquareNoADX
+	}
+}
+
+var frMul func(c, a, b *Fr) = frMulADX
+
+var frSquare func(c, a *Fr) = frSquareADX
+
+//go:noescape
+func frAdd(c, a, b *Fr)
+
+//go:noescape
+func frAddAssign(a, b *Fr)
+
+//go:noescape
+func frLaddAssign(a, b *Fr)
+
+//go:noescape
+func frSub(c, a, b *Fr)
+
+//go:noescape
+func frSubAssign(a, b *Fr)
+
+//go:noescape
+func frLsubAssign(a, b *Fr) uint64
+
+//go:noescape
+func frNeg(c, a *Fr)
+
+//go:noescape
+func frDouble(c, a *Fr)
+
+//go:noescape
+func frDoubleAssign(a *Fr)
+
+//go:noescape
+func frMulNoADX(c, a, b *Fr)
+
+//go:noescape
+func frMulADX(c, a, b *Fr)
+
+//go:noescape
+func frSquareNoADX(c, a *Fr)
+
+//go:noescape
+func frSquareADX(c, a *Fr)
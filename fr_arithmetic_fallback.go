@@ -0,0 +1,208 @@
+//go:build !amd64 || generic
+// +build !amd64 generic
+
+// Code generated by fieldgen -modulus 0x30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f0000001 -type Fr -prefix fr -output fr_arithmetic. DO NOT EDIT.
+
+package bn254
+
+import "math/bits"
+
+// Generic versions of field arithmetic. Reductions select results with masks
+// rather than branches.
+
+func frAdd(z, x, y *Fr) {
+	var c, b uint64
+	var t, s [4]uint64
+	t[0], c = bits.Add64(x[0], y[0], 0)
+	t[1], c = bits.Add64(x[1], y[1], c)
+	t[2], c = bits.Add64(x[2], y[2], c)
+	t[3], c = bits.Add64(x[3], y[3], c)
+	s[0], b = bits.Sub64(t[0], 0x43e1f593f0000001, 0)
+	s[1], b = bits.Sub64(t[1], 0x2833e84879b97091, b)
+	s[2], b = bits.Sub64(t[2], 0xb85045b68181585d, b)
+	s[3], b = bits.Sub64(t[3], 0x30644e72e131a029, b)
+	_, b = bits.Sub64(c, 0, b)
+	// keep t - p if it does not borrow
+	mask := b - 1
+	z[0] = (s[0] & mask) | (t[0] &^ mask)
+	z[1] = (s[1] & mask) | (t[1] &^ mask)
+	z[2] = (s[2] & mask) | (t[2] &^ mask)
+	z[3] = (s[3] & mask) | (t[3] &^ mask)
+}
+
+func frAddAssign(z, y *Fr) {
+	frAdd(z, z, y)
+}
+
+func frLaddAssign(z, y *Fr) {
+	var c uint64
+	z[0], c = bits.Add64(z[0], y[0], 0)
+	z[1], c = bits.Add64(z[1], y[1], c)
+	z[2], c = bits.Add64(z[2], y[2], c)
+	z[3], _ = bits.Add64(z[3], y[3], c)
+}
+
+func frDouble(z, x *Fr) {
+	frAdd(z, x, x)
+}
+
+func frDoubleAssign(z *Fr) {
+	frAdd(z, z, z)
+}
+
+func frSub(z, x, y *Fr) {
+	var b, c uint64
+	var t [4]uint64
+	t[0], b = bits.Sub64(x[0], y[0], 0)
+	t[1], b = bits.Sub64(x[1], y[1], b)
+	t[2], b = bits.Sub64(x[2], y[2], b)
+	t[3], b = bits.Sub64(x[3], y[3], b)
+	// add p back if subtraction borrows
+	mask := -b
+	z[0], c = bits.Add64(t[0], 0x43e1f593f0000001&mask, 0)
+	z[1], c = bits.Add64(t[1], 0x2833e84879b97091&mask, c)
+	z[2], c = bits.Add64(t[2], 0xb85045b68181585d&mask, c)
+	z[3], _ = bits.Add64(t[3], 0x30644e72e131a029&mask, c)
+}
+
+func frSubAssign(z, y *Fr) {
+	frSub(z, z, y)
+}
+
+func frLsubAssign(z, y *Fr) uint64 {
+	var b uint64
+	z[0], b = bits.Sub64(z[0], y[0], 0)
+	z[1], b = bits.Sub64(z[1], y[1], b)
+	z[2], b = bits.Sub64(z[2], y[2], b)
+	z[3], b = bits.Sub64(z[3], y[3], b)
+	return b
+}
+
+func frNeg(z, x *Fr) {
+	var b uint64
+	var t [4]uint64
+	t[0], b = bits.Sub64(0x43e1f593f0000001, x[0], 0)
+	t[1], b = bits.Sub64(0x2833e84879b97091, x[1], b)
+	t[2], b = bits.Sub64(0xb85045b68181585d, x[2], b)
+	t[3], _ = bits.Sub64(0x30644e72e131a029, x[3], b)
+	// zero stays zero
+	nz := x[0] | x[1] | x[2] | x[3]
+	mask := -((nz | -nz) >> 63)
+	z[0] = t[0] & mask
+	z[1] = t[1] & mask
+	z[2] = t[2] & mask
+	z[3] = t[3] & mask
+}
+
+func frMul(z, x, y *Fr) {
+	var t [4]uint64
+	var c [3]uint64
+	{
+		// round 0
+		v := x[0]
+		c[1], c[0] = bits.Mul64(v, y[0])
+		m := c[0] * 0xc2e1f593efffffff
+		c[2] = frMadd0(m, 0x43e1f593f0000001, c[0])
+		c[1], c[0] = frMadd1(v, y[1], c[1])
+		c[2], t[0] = frMadd2(m, 0x2833e84879b97091, c[2], c[0])
+		c[1], c[0] = frMadd1(v, y[2], c[1])
+		c[2], t[1] = frMadd2(m, 0xb85045b68181585d, c[2], c[0])
+		c[1], c[0] = frMadd1(v, y[3], c[1])
+		t[3], t[2] = frMadd3(m, 0x30644e72e131a029, c[0], c[2], c[1])
+	}
+	{
+		// round 1
+		v := x[1]
+		c[1], c[0] = frMadd1(v, y[0], t[0])
+		m := c[0] * 0xc2e1f593efffffff
+		c[2] = frMadd0(m, 0x43e1f593f0000001, c[0])
+		c[1], c[0] = frMadd2(v, y[1], c[1], t[1])
+		c[2], t[0] = frMadd2(m, 0x2833e84879b97091, c[2], c[0])
+		c[1], c[0] = frMadd2(v, y[2], c[1], t[2])
+		c[2], t[1] = frMadd2(m, 0xb85045b68181585d, c[2], c[0])
+		c[1], c[0] = frMadd2(v, y[3], c[1], t[3])
+		t[3], t[2] = frMadd3(m, 0x30644e72e131a029, c[0], c[2], c[1])
+	}
+	{
+		// round 2
+		v := x[2]
+		c[1], c[0] = frMadd1(v, y[0], t[0])
+		m := c[0] * 0xc2e1f593efffffff
+		c[2] = frMadd0(m, 0x43e1f593f0000001, c[0])
+		c[1], c[0] = frMadd2(v, y[1], c[1], t[1])
+		c[2], t[0] = frMadd2(m, 0x2833e84879b97091, c[2], c[0])
+		c[1], c[0] = frMadd2(v, y[2], c[1], t[2])
+		c[2], t[1] = frMadd2(m, 0xb85045b68181585d, c[2], c[0])
+		c[1], c[0] = frMadd2(v, y[3], c[1], t[3])
+		t[3], t[2] = frMadd3(m, 0x30644e72e131a029, c[0], c[2], c[1])
+	}
+	{
+		// round 3
+		v := x[3]
+		c[1], c[0] = frMadd1(v, y[0], t[0])
+		m := c[0] * 0xc2e1f593efffffff
+		c[2] = frMadd0(m, 0x43e1f593f0000001, c[0])
+		c[1], c[0] = frMadd2(v, y[1], c[1], t[1])
+		c[2], z[0] = frMadd2(m, 0x2833e84879b97091, c[2], c[0])
+		c[1], c[0] = frMadd2(v, y[2], c[1], t[2])
+		c[2], z[1] = frMadd2(m, 0xb85045b68181585d, c[2], c[0])
+		c[1], c[0] = frMadd2(v, y[3], c[1], t[3])
+		z[3], z[2] = frMadd3(m, 0x30644e72e131a029, c[0], c[2], c[1])
+	}
+	// keep z - p if it does not borrow
+	var s [4]uint64
+	var k uint64
+	s[0], k = bits.Sub64(z[0], 0x43e1f593f0000001, 0)
+	s[1], k = bits.Sub64(z[1], 0x2833e84879b97091, k)
+	s[2], k = bits.Sub64(z[2], 0xb85045b68181585d, k)
+	s[3], k = bits.Sub64(z[3], 0x30644e72e131a029, k)
+	mask := k - 1
+	z[0] = (s[0] & mask) | (z[0] &^ mask)
+	z[1] = (s[1] & mask) | (z[1] &^ mask)
+	z[2] = (s[2] & mask) | (z[2] &^ mask)
+	z[3] = (s[3] & mask) | (z[3] &^ mask)
+}
+
+// frMadd0 hi = a*b + c (discards lo bits)
+func frMadd0(a, b, c uint64) (hi uint64) {
+	var carry, lo uint64
+	hi, lo = bits.Mul64(a, b)
+	_, carry = bits.Add64(lo, c, 0)
+	hi, _ = bits.Add64(hi, 0, carry)
+	return
+}
+
+// frMadd1 hi, lo = a*b + c
+func frMadd1(a, b, c uint64) (hi uint64, lo uint64) {
+	var carry uint64
+	hi, lo = bits.Mul64(a, b)
+	lo, carry = bits.Add64(lo, c, 0)
+	hi, _ = bits.Add64(hi, 0, carry)
+	return
+}
+
+// frMadd2 hi, lo = a*b + c + d
+func frMadd2(a, b, c, d uint64) (hi uint64, lo uint64) {
+	var carry uint64
+	hi, lo = bits.Mul64(a, b)
+	c, carry = bits.Add64(c, d, 0)
+	hi, _ = bits.Add64(hi, 0, carry)
+	lo, carry = bits.Add64(lo, c, 0)
+	hi, _ = bits.Add64(hi, 0, carry)
+	return
+}
+
+// frMadd3 hi, lo = a*b + c + d + e*2^64
+func frMadd3(a, b, c, d, e uint64) (hi uint64, lo uint64) {
+	var carry uint64
+	hi, lo = bits.Mul64(a, b)
+	c, carry = bits.Add64(c, d, 0)
+	hi, _ = bits.Add64(hi, 0, carry)
+	lo, carry = bits.Add64(lo, c, 0)
+	hi, _ = bits.Add64(hi, e, carry)
+	return
+}
+
+func frSquare(z, x *Fr) {
+	frMul(z, x, x)
+}
@@ -0,0 +1,128 @@
+package bn254
+
+import (
+	"bytes"
+	"crypto/rand"
+	"math/big"
+	"testing"
+)
+
+func TestFrParameters(t *testing.T) {
+	if (*fe)(&frModulus).big().Cmp(q) != 0 {
+		t.Fatal("bad modulus")
+	}
+	r := new(big.Int).Lsh(big.NewInt(1), 256)
+	if (*fe)(frR1).big().Cmp(new(big.Int).Mod(r, q)) != 0 {
+		t.Fatal("bad r1")
+	}
+	if (*fe)(frR2).big().Cmp(new(big.Int).Exp(r, big.NewInt(2), q)) != 0 {
+		t.Fatal("bad r2")
+	}
+	// inp = -q^(-1) mod 2^64
+	if frInp*frModulus[0] != ^uint64(0) {
+		t.Fatal("bad inp")
+	}
+}
+
+func TestFrSerialization(t *testing.T) {
+	for i := 0; i < fuz; i++ {
+		a, _ := new(Fr).Rand(rand.Reader)
+		b, err := FrFromBytes(a.ToBytes())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !a.Equal(b) {
+			t.Fatal("bad serialization")
+		}
+		if !new(Fr).SetBig(a.Big()).Equal(a) {
+			t.Fatal("bad big conversion")
+		}
+	}
+	if _, err := FrFromBytes(padBytes(q.Bytes(), 32)); err == nil {
+		t.Fatal("modulus is not a valid encoding")
+	}
+	if !new(Fr).SetBig(big.NewInt(-1)).Equal(new(Fr).Neg(new(Fr).One())) {
+		t.Fatal("negative integers should be reduced")
+	}
+	if new(Fr).SetUint64(7).Big().Uint64() != 7 {
+		t.Fatal("bad uint64 conversion")
+	}
+}
+
+func TestFrArithmeticCrossAgainstBigInt(t *testing.T) {
+	edges := []*Fr{new(Fr).Zero(), new(Fr).One(), new(Fr).SetBig(new(big.Int).Sub(q, big.NewInt(1)))}
+	for i := 0; i < fuz; i++ {
+		a, _ := new(Fr).Rand(rand.Reader)
+		edges = append(edges, a)
+	}
+	check := func(name string, c *Fr, expected *big.Int) {
+		if !bytes.Equal(c.ToBytes(), padBytes(new(big.Int).Mod(expected, q).Bytes(), 32)) {
+			t.Fatalf("cross test against big.Int is not satisfied: %s", name)
+		}
+	}
+	for _, a := range edges {
+		for _, b := range edges {
+			big_a, big_b := a.Big(), b.Big()
+			c := new(Fr)
+			check("add", c.Add(a, b), new(big.Int).Add(big_a, big_b))
+			check("sub", c.Sub(a, b), new(big.Int).Sub(big_a, big_b))
+			check("mul", c.Mul(a, b), new(big.Int).Mul(big_a, big_b))
+		}
+		big_a := a.Big()
+		c := new(Fr)
+		check("double", c.Double(a), new(big.Int).Lsh(big_a, 1))
+		check("neg", c.Neg(a), new(big.Int).Neg(big_a))
+		check("square", c.Square(a), new(big.Int).Mul(big_a, big_a))
+		e, _ := rand.Int(rand.Reader, q)
+		check("exp", c.Exp(a, e), new(big.Int).Exp(big_a, e, q))
+		inv := new(big.Int).ModInverse(big_a, q)
+		if inv == nil {
+			inv = new(big.Int)
+		}
+		check("inverse", c.Inverse(a), inv)
+	}
+}
+
+func TestFrAssignedOperations(t *testing.T) {
+	for i := 0; i < fuz; i++ {
+		a, _ := new(Fr).Rand(rand.Reader)
+		b, _ := new(Fr).Rand(rand.Reader)
+		c_1, c_2 := new(Fr).Set(a), new(Fr)
+		frAddAssign(c_1, b)
+		frAdd(c_2, a, b)
+		if !c_1.Equal(c_2) {
+			t.Fatal("a + b == a += b")
+		}
+		c_1.Set(a)
+		frSubAssign(c_1, b)
+		frSub(c_2, a, b)
+		if !c_1.Equal(c_2) {
+			t.Fatal("a - b == a -= b")
+		}
+		c_1.Set(a)
+		frDoubleAssign(c_1)
+		frDouble(c_2, a)
+		if !c_1.Equal(c_2) {
+			t.Fatal("2a == a *= 2")
+		}
+		// lazy addition and subtraction are plain integer operations
+		c_1.Set(a)
+		frLaddAssign(c_1, b)
+		if (*fe)(c_1).big().Cmp(new(big.Int).Add((*fe)(a).big(), (*fe)(b).big())) != 0 {
+			t.Fatal("lazy addition is not satisfied")
+		}
+		if frLsubAssign(c_1, b) != 0 || !c_1.Equal(a) {
+			t.Fatal("lazy subtraction is not satisfied")
+		}
+	}
+}
+
+func BenchmarkFrMul(t *testing.B) {
+	a, _ := new(Fr).Rand(rand.Reader)
+	b, _ := new(Fr).Rand(rand.Reader)
+	c := new(Fr)
+	t.ResetTimer()
+	for i := 0; i < t.N; i++ {
+		c.Mul(a, b)
+	}
+}
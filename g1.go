@@ -30,6 +30,7 @@ type tempG1 struct {
 }
 
 // G1 is struct for G1 group.
+// G1 holds scratch space and is not safe for concurrent use, see SafeG1.
 type G1 struct {
 	tempG1
 }
@@ -95,6 +96,28 @@ func (g *G1) FromBytes(in []byte) (*PointG1, error) {
 	return p, nil
 }
 
+// FromMontgomeryLE constructs a new point given x and y coordinates each encoded in 32 bytes
+// little endian Montgomery form as in snarkjs files.
+// Point (0, 0) is considered as infinity.
+// Unlike FromBytes it does not check whether point is on curve.
+func (g *G1) FromMontgomeryLE(in []byte) (*PointG1, error) {
+	if len(in) != 64 {
+		return nil, errors.New("input string should be equal 64 bytes")
+	}
+	p0, err := fromMontgomeryLE(in[:32])
+	if err != nil {
+		return nil, err
+	}
+	p1, err := fromMontgomeryLE(in[32:])
+	if err != nil {
+		return nil, err
+	}
+	if p0.isZero() && p1.isZero() {
+		return g.Zero(), nil
+	}
+	return &PointG1{*p0, *p1, *new(fe).one()}, nil
+}
+
 // ToBytes serializes a point into bytes in uncompressed form.
 // ToBytes does not take zcash flags into account.
 // ToBytes returns (0, 0) if point is infinity.
@@ -285,6 +308,25 @@ func (g *G1) Neg(r, p *PointG1) *PointG1 {
 	return r
 }
 
+// Select assigns p1 to r if cond is one and p2 if cond is zero. Behaviour is undefined
+// for other values of cond. Selection is done in constant time.
+func (g *G1) Select(r, p1, p2 *PointG1, cond uint64) *PointG1 {
+	t := new(PointG1).Set(p2)
+	t[0].cmov(&p1[0], cond)
+	t[1].cmov(&p1[1], cond)
+	t[2].cmov(&p1[2], cond)
+	return r.Set(t)
+}
+
+// CondNeg assigns -p to r if cond is one and p if cond is zero. Behaviour is undefined
+// for other values of cond. Negation is done in constant time.
+func (g *G1) CondNeg(r, p *PointG1, cond uint64) *PointG1 {
+	r[0].set(&p[0])
+	cneg(&r[1], &p[1], cond)
+	r[2].set(&p[2])
+	return r
+}
+
 // Sub subtracts two G1 points p1, p2 and assigns the result to point at first argument.
 func (g *G1) Sub(c, a, b *PointG1) *PointG1 {
 	d := &PointG1{}
@@ -488,3 +530,75 @@ func (g *G1) HashToCurveFT(msg, domain []byte) (*PointG1, error) {
 	g.Add(p0, p0, p1)
 	return g.Affine(p0), nil
 }
+
+// MapToPointSVDW applies Shallue-van de Woestijne map to given 32 bytes input. Unlike
+// MapToPointFT it runs in constant time so that input is not leaked through timing.
+func (g *G1) MapToPointSVDW(in []byte) (*PointG1, error) {
+	u, err := fromBytesUnchecked(in)
+	if err != nil {
+		return nil, err
+	}
+	return g.mapToPointSVDW(u), nil
+}
+
+// mapToPointSVDW is the straight line Shallue-van de Woestijne map with Z = 1. All three
+// candidates are evaluated and the result is picked with conditional moves.
+func (g *G1) mapToPointSVDW(u *fe) *PointG1 {
+	tv1, tv2, tv3, tv4 := new(fe), new(fe), new(fe), new(fe)
+	square(tv1, u)
+	mul(tv1, tv1, svdwC1)
+	add(tv2, one, tv1)
+	sub(tv1, one, tv1)
+	mul(tv3, tv1, tv2)
+	inverseCT(tv3, tv3)
+	mul(tv4, u, tv1)
+	mul(tv4, tv4, tv3)
+	mul(tv4, tv4, svdwC3)
+
+	// x1 = c2 - tv4
+	x1, y1, gx := new(fe), new(fe), new(fe)
+	sub(x1, svdwC2, tv4)
+	square(gx, x1)
+	mul(gx, gx, x1)
+	add(gx, gx, b)
+	e1 := sqrtCT(y1, gx)
+
+	// x2 = c2 + tv4
+	x2, y2 := new(fe), new(fe)
+	add(x2, svdwC2, tv4)
+	square(gx, x2)
+	mul(gx, gx, x2)
+	add(gx, gx, b)
+	e2 := sqrtCT(y2, gx) &^ e1
+
+	// x3 = Z + c4 * (tv2^2 * tv3)^2
+	x3, y3 := new(fe), new(fe)
+	square(x3, tv2)
+	mul(x3, x3, tv3)
+	square(x3, x3)
+	mul(x3, x3, svdwC4)
+	add(x3, x3, one)
+	square(gx, x3)
+	mul(gx, gx, x3)
+	add(gx, gx, b)
+	sqrtCT(y3, gx)
+
+	x3.cmov(x1, e1).cmov(x2, e2)
+	y3.cmov(y1, e1).cmov(y2, e2)
+	cneg(y3, y3, u.sgn0()^y3.sgn0())
+	return &PointG1{*x3, *y3, *one}
+}
+
+// HashToCurveSVDW hashes given message into a G1 point following the hash to curve
+// specification with expand_message_xmd using SHA-256 and the Shallue-van de Woestijne map.
+// Mapping is done in constant time.
+func (g *G1) HashToCurveSVDW(msg, domain []byte) (*PointG1, error) {
+	hashRes, err := hashToFpXMDSHA256(msg, domain, 2)
+	if err != nil {
+		return nil, err
+	}
+	p0 := g.mapToPointSVDW(hashRes[0])
+	p1 := g.mapToPointSVDW(hashRes[1])
+	g.Add(p0, p0, p1)
+	return g.Affine(p0), nil
+}
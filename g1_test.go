@@ -3,6 +3,7 @@ package bn254
 import (
 	"bytes"
 	"crypto/rand"
+	"encoding/binary"
 	"encoding/hex"
 	"math/big"
 	"testing"
@@ -35,6 +36,42 @@ func TestG1Serialization(t *testing.T) {
 	}
 }
 
+func TestG1SerializationMontgomeryLE(t *testing.T) {
+	g1 := NewG1()
+	for i := 0; i < fuz; i++ {
+		a := g1.Affine(g1.rand())
+		in := make([]byte, 64)
+		for j := 0; j < 4; j++ {
+			binary.LittleEndian.PutUint64(in[j*8:], a[0][j])
+			binary.LittleEndian.PutUint64(in[32+j*8:], a[1][j])
+		}
+		b, err := g1.FromMontgomeryLE(in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !g1.Equal(a, b) {
+			t.Fatal("bad serialization")
+		}
+	}
+	zero, err := g1.FromMontgomeryLE(make([]byte, 64))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !g1.IsZero(zero) {
+		t.Fatal("zero bytes should be infinity")
+	}
+	in := make([]byte, 64)
+	for j := 0; j < 4; j++ {
+		binary.LittleEndian.PutUint64(in[j*8:], modulus[j])
+	}
+	if _, err := g1.FromMontgomeryLE(in); err == nil {
+		t.Fatal("coordinate larger than modulus should be rejected")
+	}
+	if _, err := g1.FromMontgomeryLE(in[:63]); err == nil {
+		t.Fatal("short input should be rejected")
+	}
+}
+
 func TestG1IsOnCurve(t *testing.T) {
 	g := NewG1()
 	zero := g.Zero()
@@ -117,6 +154,34 @@ func TestG1AdditiveProperties(t *testing.T) {
 	}
 }
 
+func TestG1ConstantTimeSelection(t *testing.T) {
+	g := NewG1()
+	for i := 0; i < fuz; i++ {
+		a, b := g.rand(), g.rand()
+		r := g.New()
+		if !g.Equal(g.Select(r, a, b, 1), a) {
+			t.Fatal("select with one condition should pick first point")
+		}
+		if !g.Equal(g.Select(r, a, b, 0), b) {
+			t.Fatal("select with zero condition should pick second point")
+		}
+		r.Set(a)
+		if !g.Equal(g.Select(r, b, r, 1), b) {
+			t.Fatal("select should allow aliasing")
+		}
+		if !g.Equal(g.CondNeg(r, a, 0), a) {
+			t.Fatal("conditional negation with zero condition should be noop")
+		}
+		if !g.Equal(g.CondNeg(r, a, 1), g.Neg(g.New(), a)) {
+			t.Fatal("conditional negation with one condition should negate")
+		}
+		g.CondNeg(r, r, 1)
+		if !g.Equal(r, a) {
+			t.Fatal("-(-a) == a")
+		}
+	}
+}
+
 func TestG1MultiplicativeProperties(t *testing.T) {
 	g := NewG1()
 	t0, t1 := g.New(), g.New()
@@ -450,6 +515,67 @@ func TestG1MapToCurveFT(t *testing.T) {
 	}
 }
 
+func TestG1HashToCurveSVDW(t *testing.T) {
+	// BN254G1_XMD:SHA-256_SVDW_RO_ suite
+	domain := []byte("QUUX-V01-CS02-with-BN254G1_XMD:SHA-256_SVDW_RO_")
+	vectors := []struct {
+		msg  string
+		x, y string
+	}{
+		{
+			"",
+			"0x0a976ab906170db1f9638d376514dbf8c42aef256a54bbd48521f20749e59e86",
+			"0x02925ead66b9e68bfc309b014398640ab55f6619ab59bc1fab2210ad4c4d53d5",
+		},
+		{
+			"abc",
+			"0x23f717bee89b1003957139f193e6be7da1df5f1374b26a4643b0378b5baf53d1",
+			"0x04142f826b71ee574452dbc47e05bc3e1a647478403a7ba38b7b93948f4e151d",
+		},
+	}
+	g := NewG1()
+	for _, v := range vectors {
+		p, err := g.HashToCurveSVDW([]byte(v.msg), domain)
+		if err != nil {
+			t.Fatal(err)
+		}
+		out := g.ToBytes(p)
+		x, _ := hex.DecodeString(v.x[2:])
+		y, _ := hex.DecodeString(v.y[2:])
+		if !bytes.Equal(out[:32], x) {
+			t.Fatal("x")
+		}
+		if !bytes.Equal(out[32:], y) {
+			t.Fatal("y")
+		}
+	}
+}
+
+func TestG1MapToPointSVDW(t *testing.T) {
+	g := NewG1()
+	// zero and 1/2 are exceptional inputs where inversion is applied to zero
+	inputs := []*fe{new(fe).zero(), new(fe).set(twoInv), new(fe)}
+	neg(inputs[2], twoInv)
+	for i := 0; i < fuz; i++ {
+		u, _ := new(fe).rand(rand.Reader)
+		inputs = append(inputs, u)
+	}
+	for _, u := range inputs {
+		p := g.mapToPointSVDW(u)
+		if !g.IsOnCurve(p) {
+			t.Fatal("mapped point must be on curve")
+		}
+		if p[1].sgn0() != u.sgn0() {
+			t.Fatal("sign of y must follow sign of input")
+		}
+	}
+	in := make([]byte, 32)
+	_, _ = rand.Read(in)
+	if _, err := g.MapToPointSVDW(in); err != nil {
+		t.Fatal(err)
+	}
+}
+
 func BenchmarkG1Add(t *testing.B) {
 	g1 := NewG1()
 	a, b, c := g1.rand(), g1.rand(), PointG1{}
@@ -467,3 +593,67 @@ func BenchmarkG1Mul(t *testing.B) {
 		g1.MulScalar(&c, a, e)
 	}
 }
+
+func TestG1MapToPointFTPreimages(t *testing.T) {
+	g := NewG1()
+	for i := 0; i < fuz; i++ {
+		u, _ := new(fe).rand(rand.Reader)
+		p, err := g.mapToPointFT(u)
+		if err != nil {
+			t.Fatal(err)
+		}
+		preimages := g.mapToPointFTPreimages(p)
+		if len(preimages) == 0 || len(preimages) > 4 {
+			t.Fatal("bad number of preimages")
+		}
+		found := false
+		for _, v := range preimages {
+			found = found || v.equal(u)
+			q, err := g.mapToPointFT(v)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !g.Equal(p, q) {
+				t.Fatal("bad preimage")
+			}
+		}
+		if !found {
+			t.Fatal("preimage is not found")
+		}
+	}
+}
+
+func TestG1EncodeUniform(t *testing.T) {
+	g := NewG1()
+	points := []*PointG1{g.Zero(), g.one()}
+	for i := 0; i < fuz; i++ {
+		points = append(points, g.rand())
+	}
+	for _, p := range points {
+		encoded, err := g.EncodeUniform(p, rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(encoded) != 64 {
+			t.Fatal("bad encoding length")
+		}
+		decoded, err := g.DecodeUniform(encoded)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !g.Equal(p, decoded) {
+			t.Fatal("bad uniform encoding")
+		}
+	}
+	for i := 0; i < fuz; i++ {
+		in := make([]byte, 64)
+		_, _ = rand.Read(in)
+		p, err := g.DecodeUniform(in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !g.IsOnCurve(p) {
+			t.Fatal("decoded point must be on curve")
+		}
+	}
+}
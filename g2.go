@@ -32,6 +32,7 @@ type tempG2 struct {
 }
 
 // G2 is struct for G2 group.
+// G2 holds scratch space and is not safe for concurrent use, see SafeG2.
 type G2 struct {
 	f *fp2
 	tempG2
@@ -105,6 +106,30 @@ func (g *G2) FromBytes(in []byte) (*PointG2, error) {
 	return p, nil
 }
 
+// FromMontgomeryLE constructs a new point given coordinates x0, x1, y0, y1 where x = x0 + x1 * u
+// and y = y0 + y1 * u, each encoded in 32 bytes little endian Montgomery form as in snarkjs
+// files.
+// Point (0, 0) is considered as infinity.
+// Unlike FromBytes it does not check whether point is on curve.
+func (g *G2) FromMontgomeryLE(in []byte) (*PointG2, error) {
+	if len(in) != 128 {
+		return nil, errors.New("input string should be equal 128 bytes")
+	}
+	p := &PointG2{}
+	for i := 0; i < 4; i++ {
+		c, err := fromMontgomeryLE(in[i*32 : (i+1)*32])
+		if err != nil {
+			return nil, err
+		}
+		p[i/2][i%2].set(c)
+	}
+	if p[0].isZero() && p[1].isZero() {
+		return g.Zero(), nil
+	}
+	p[2].one()
+	return p, nil
+}
+
 // ToBytes serializes a point into bytes in uncompressed form,
 // does not take zcash flags into account,
 // returns (0, 0) if point is infinity.
@@ -300,6 +325,25 @@ func (g *G2) Neg(r, p *PointG2) *PointG2 {
 	return r
 }
 
+// Select assigns p1 to r if cond is one and p2 if cond is zero. Behaviour is undefined
+// for other values of cond. Selection is done in constant time.
+func (g *G2) Select(r, p1, p2 *PointG2, cond uint64) *PointG2 {
+	t := new(PointG2).Set(p2)
+	t[0].cmov(&p1[0], cond)
+	t[1].cmov(&p1[1], cond)
+	t[2].cmov(&p1[2], cond)
+	return r.Set(t)
+}
+
+// CondNeg assigns -p to r if cond is one and p if cond is zero. Behaviour is undefined
+// for other values of cond. Negation is done in constant time.
+func (g *G2) CondNeg(r, p *PointG2, cond uint64) *PointG2 {
+	r[0].set(&p[0])
+	g.f.cneg(&r[1], &p[1], cond)
+	r[2].set(&p[2])
+	return r
+}
+
 // Sub subtracts two G2 points p1, p2 and assigns the result to point at first argument.
 func (g *G2) Sub(c, a, b *PointG2) *PointG2 {
 	d := &PointG2{}
@@ -2,6 +2,7 @@ package bn254
 
 import (
 	"crypto/rand"
+	"encoding/binary"
 	"math/big"
 	"testing"
 )
@@ -42,6 +43,37 @@ func TestG2Serialization(t *testing.T) {
 	}
 }
 
+func TestG2SerializationMontgomeryLE(t *testing.T) {
+	g2 := NewG2()
+	for i := 0; i < fuz; i++ {
+		a := g2.Affine(g2.rand())
+		in := make([]byte, 128)
+		// coordinates are in order of x0, x1, y0, y1
+		for k := 0; k < 4; k++ {
+			for j := 0; j < 4; j++ {
+				binary.LittleEndian.PutUint64(in[k*32+j*8:], a[k/2][k%2][j])
+			}
+		}
+		b, err := g2.FromMontgomeryLE(in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !g2.Equal(a, b) {
+			t.Fatal("bad serialization")
+		}
+	}
+	zero, err := g2.FromMontgomeryLE(make([]byte, 128))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !g2.IsZero(zero) {
+		t.Fatal("zero bytes should be infinity")
+	}
+	if _, err := g2.FromMontgomeryLE(make([]byte, 127)); err == nil {
+		t.Fatal("short input should be rejected")
+	}
+}
+
 func TestG2IsOnCurve(t *testing.T) {
 	g := NewG2()
 	zero := g.Zero()
@@ -125,6 +157,34 @@ func TestG2AdditiveProperties(t *testing.T) {
 	}
 }
 
+func TestG2ConstantTimeSelection(t *testing.T) {
+	g := NewG2()
+	for i := 0; i < fuz; i++ {
+		a, b := g.rand(), g.rand()
+		r := g.New()
+		if !g.Equal(g.Select(r, a, b, 1), a) {
+			t.Fatal("select with one condition should pick first point")
+		}
+		if !g.Equal(g.Select(r, a, b, 0), b) {
+			t.Fatal("select with zero condition should pick second point")
+		}
+		r.Set(a)
+		if !g.Equal(g.Select(r, b, r, 1), b) {
+			t.Fatal("select should allow aliasing")
+		}
+		if !g.Equal(g.CondNeg(r, a, 0), a) {
+			t.Fatal("conditional negation with zero condition should be noop")
+		}
+		if !g.Equal(g.CondNeg(r, a, 1), g.Neg(g.New(), a)) {
+			t.Fatal("conditional negation with one condition should negate")
+		}
+		g.CondNeg(r, r, 1)
+		if !g.Equal(r, a) {
+			t.Fatal("-(-a) == a")
+		}
+	}
+}
+
 func TestG2MultiplicativeProperties(t *testing.T) {
 	g := NewG2()
 	t0, t1 := g.New(), g.New()
@@ -1,13 +1,16 @@
 package bn254
 
 import (
+	"errors"
 	"math/big"
+	"math/bits"
 )
 
 // E is type for target group element
 type E = fe12
 
 // GT is type for target multiplicative group GT.
+// GT holds scratch space and is not safe for concurrent use, see SafeGT.
 type GT struct {
 	fp12 *fp12
 }
@@ -44,13 +47,71 @@ func (g *GT) Q() *big.Int {
 	return new(big.Int).Set(q)
 }
 
-// FromBytes expects 576 byte input and returns target group element
+// FromBytes expects 384 byte input and returns target group element
 // FromBytes returns error if given element is not on correct subgroup.
 func (g *GT) FromBytes(in []byte) (*E, error) {
 	e, err := g.fp12.fromBytes(in)
 	if err != nil {
 		return nil, err
 	}
+	if !g.InCorrectSubgroup(e) {
+		return nil, errors.New("element is not in correct subgroup")
+	}
+	return e, nil
+}
+
+// FromBytesUnchecked expects 384 byte input and returns target group element
+// without subgroup check. It should only be used for trusted inputs.
+func (g *GT) FromBytesUnchecked(in []byte) (*E, error) {
+	return g.fp12.fromBytes(in)
+}
+
+// InCorrectSubgroup checks whether given element is in order q subgroup of multiplicative group.
+func (g *GT) InCorrectSubgroup(e *E) bool {
+	// q divides p^4 - p^2 + 1 so the element must be in cyclotomic subgroup
+	if !g.fp12.isCyclotomic(e) {
+		return false
+	}
+	// q = p - 6 * u^2 so that e^q == 1 iff e^p == e^(6 * u^2)
+	t0, t1 := new(fe12), new(fe12)
+	g.fp12.frobeniusMap(t0, e, 1)
+	g.fp12.cyclotomicExpCompressed(t1, e, sixUSquare)
+	return t0.equal(t1)
+}
+
+// ToCompressed serializes target group element into 192 bytes using torus T2 compression.
+// Given element is expected to be in cyclotomic subgroup such as pairing results.
+func (g *GT) ToCompressed(e *E) []byte {
+	m := new(fe6)
+	g.fp12.compress(m, e)
+	return g.fp12.fp6.toBytes(m)
+}
+
+// FromCompressed expects 192 byte torus compressed input and returns target group element.
+// FromCompressed returns error if given element is not on correct subgroup.
+func (g *GT) FromCompressed(in []byte) (*E, error) {
+	e, err := g.FromCompressedUnchecked(in)
+	if err != nil {
+		return nil, err
+	}
+	if !g.InCorrectSubgroup(e) {
+		return nil, errors.New("element is not in correct subgroup")
+	}
+	return e, nil
+}
+
+// FromCompressedUnchecked expects 192 byte torus compressed input and returns target group element
+// without subgroup check. It should only be used for trusted inputs.
+func (g *GT) FromCompressedUnchecked(in []byte) (*E, error) {
+	if len(in) != 192 {
+		return nil, errors.New("input string should be equal 192 bytes")
+	}
+	m, err := g.fp12.fp6.fromBytes(in)
+	if err != nil {
+		return nil, err
+	}
+	e := new(E)
+	g.fp12.decompress(e, m)
 	return e, nil
 }
 
@@ -85,8 +146,285 @@ func (g *GT) Square(c, a *E) {
 }
 
 // Exp exponents an element `a` by a scalar `s` and assigns the result to the element in first argument.
+// Frobenius map acts as exponentiation by p on GT so the scalar is decomposed into four ~64 bit pieces and
+// a joint exponentiation of a, a^p, a^(p^2) and a^(p^3) is applied. Given element is expected to be in GT,
+// such as pairing results and elements decoded with FromBytes or FromCompressed, result is wrong otherwise.
+// Exp is not constant time, use ExpConstantTime for secret scalars.
 func (g *GT) Exp(c, a *E, s *big.Int) {
-	g.fp12.cyclotomicExp(c, a, s)
+	bases, k := make([][4]fe12, 1), make([][4]*big.Int, 1)
+	g.frobeniusBases(&bases[0], &k[0], a, s)
+	c.set(g.multiExpInterleaved(bases, k))
+}
+
+// MultiExp calculates multi exponentiation. Given pairs of target group element and scalar values
+// (e_0, s_0), (e_1, s_1), ... (e_n, s_n) calculates r = e_0^s_0 * e_1^s_1 * ... * e_n^s_n
+// Given elements are expected to be in GT. Length of elements and scalars are expected to be equal,
+// otherwise an error is returned. Result is assigned to element at first argument. Given scalars are not modified.
+// MultiExp is not constant time.
+func (g *GT) MultiExp(r *E, elems []*E, scalars []*big.Int) (*E, error) {
+	if len(elems) != len(scalars) {
+		return nil, errors.New("element and scalar vectors should be in same length")
+	}
+	bases, k := make([][4]fe12, len(elems)), make([][4]*big.Int, len(elems))
+	for i := 0; i < len(elems); i++ {
+		g.frobeniusBases(&bases[i], &k[i], elems[i], scalars[i])
+	}
+	if len(elems) < 32 {
+		return r.Set(g.multiExpInterleaved(bases, k)), nil
+	}
+	return r.Set(g.multiExpPippenger(bases, k)), nil
+}
+
+// frobeniusBases decomposes scalar `s` into four pieces and sets bases to a, a^p, a^(p^2) and a^(p^3).
+// Bases are conjugated where the corresponding piece is negative so that all pieces are non negative.
+func (g *GT) frobeniusBases(bases *[4]fe12, k *[4]*big.Int, a *E, s *big.Int) {
+	fp12 := g.fp12
+	*k = decomposeGT(s)
+	bases[0].set(a)
+	for i := 1; i < 4; i++ {
+		fp12.frobeniusMap(&bases[i], &bases[i-1], 1)
+	}
+	for i := 0; i < 4; i++ {
+		if k[i].Sign() < 0 {
+			// inversion is conjugation in cyclotomic subgroup
+			fp12.conjugate(&bases[i], &bases[i])
+			k[i].Neg(k[i])
+		}
+	}
+}
+
+// multiExpInterleaved calculates product of bases[i][j]^k[i][j] with a precomputed table of
+// 16 products for each group of four bases and squarings shared among all groups.
+func (g *GT) multiExpInterleaved(bases [][4]fe12, k [][4]*big.Int) *fe12 {
+	fp12 := g.fp12
+	n := 0
+	tables := make([][16]fe12, len(bases))
+	for i := 0; i < len(bases); i++ {
+		// tables[i][j] is product of bases at set bits of j
+		tables[i][0].one()
+		for j := 1; j < 16; j++ {
+			l := 0
+			for j>>l&1 == 0 {
+				l++
+			}
+			fp12.mul(&tables[i][j], &tables[i][j&(j-1)], &bases[i][l])
+		}
+		for l := 0; l < 4; l++ {
+			if k[i][l].BitLen() > n {
+				n = k[i][l].BitLen()
+			}
+		}
+	}
+	z := fp12.one()
+	for b := n - 1; b >= 0; b-- {
+		fp12.cyclotomicSquare(z, z)
+		for i := 0; i < len(bases); i++ {
+			j := k[i][0].Bit(b) | k[i][1].Bit(b)<<1 | k[i][2].Bit(b)<<2 | k[i][3].Bit(b)<<3
+			if j != 0 {
+				fp12.mulAssign(z, &tables[i][j])
+			}
+		}
+	}
+	return z
+}
+
+// multiExpPippenger calculates product of bases[i][j]^k[i][j] with bucket method.
+func (g *GT) multiExpPippenger(bases [][4]fe12, k [][4]*big.Int) *fe12 {
+	fp12 := g.fp12
+	c := bits.Len(uint(4*len(bases))) - 2
+	if c < 2 {
+		c = 2
+	}
+	n := 0
+	for i := 0; i < len(bases); i++ {
+		for l := 0; l < 4; l++ {
+			if k[i][l].BitLen() > n {
+				n = k[i][l].BitLen()
+			}
+		}
+	}
+	bucket := make([]fe12, (1<<c)-1)
+	filled := make([]bool, len(bucket))
+	sum, acc := new(fe12), fp12.one()
+	for w := (n+c-1)/c - 1; w >= 0; w-- {
+		for j := 0; j < c; j++ {
+			fp12.cyclotomicSquare(acc, acc)
+		}
+		for j := 0; j < len(bucket); j++ {
+			filled[j] = false
+		}
+		for i := 0; i < len(bases); i++ {
+			for l := 0; l < 4; l++ {
+				var index uint
+				for j := c - 1; j >= 0; j-- {
+					index = index<<1 | k[i][l].Bit(w*c+j)
+				}
+				if index == 0 {
+					continue
+				}
+				if filled[index-1] {
+					fp12.mulAssign(&bucket[index-1], &bases[i][l])
+				} else {
+					bucket[index-1].set(&bases[i][l])
+					filled[index-1] = true
+				}
+			}
+		}
+		// acc = acc * bucket[0] * bucket[1]^2 * ... * bucket[2^c - 2]^(2^c - 1)
+		sum.one()
+		for j := len(bucket) - 1; j >= 0; j-- {
+			if filled[j] {
+				fp12.mulAssign(sum, &bucket[j])
+			}
+			if !sum.isOne() {
+				fp12.mulAssign(acc, sum)
+			}
+		}
+	}
+	return acc
+}
+
+// ExpConstantTime exponents an element `a` by a scalar `s` and assigns the result to the element in first argument.
+// Scalar is decomposed as in Exp with fixed width arithmetic. Negative pieces are handled by conditional
+// conjugation of bases and all pieces are processed for a fixed number of bits with a table lookup that touches
+// every entry and a multiplication at each bit, so that the sequence of field operations doesn't depend on the
+// scalar. Given element is expected to be in GT.
+func (g *GT) ExpConstantTime(c, a *E, s *Fr) {
+	fp12 := g.fp12
+	k := new(Fr)
+	frMul(k, s, &Fr{1})
+	d := decomposeGTConstantTime((*[4]uint64)(k))
+	var bases [4]fe12
+	var abs [4][2]uint64
+	t := new(fe12)
+	bases[0].set(a)
+	for i := 1; i < 4; i++ {
+		fp12.frobeniusMap(&bases[i], &bases[i-1], 1)
+	}
+	for i := 0; i < 4; i++ {
+		neg := d[i][1] >> 63
+		fp12.conjugate(t, &bases[i])
+		bases[i].cmov(t, neg)
+		mask := -neg
+		var b uint64
+		abs[i][0], b = bits.Sub64(d[i][0]^mask, mask, 0)
+		abs[i][1], _ = bits.Sub64(d[i][1]^mask, mask, b)
+	}
+	// table[j] is product of bases at set bits of j
+	table := [16]fe12{}
+	table[0].one()
+	for j := 1; j < 16; j++ {
+		l := 0
+		for j>>l&1 == 0 {
+			l++
+		}
+		fp12.mul(&table[j], &table[j&(j-1)], &bases[l])
+	}
+	z := fp12.one()
+	for b := gtDecompositionBits - 1; b >= 0; b-- {
+		fp12.cyclotomicSquare(z, z)
+		var w uint64
+		for i := 0; i < 4; i++ {
+			w |= (abs[i][b/64] >> uint(b%64) & 1) << uint(i)
+		}
+		t.one()
+		for j := uint64(1); j < 16; j++ {
+			// eq is one if j == w and zero otherwise
+			eq := ((j ^ w) - 1) >> 63
+			t.cmov(&table[j], eq)
+		}
+		fp12.mulAssign(z, t)
+	}
+	c.set(z)
+}
+
+// gtDecompositionBits bounds bit length of absolute values of pieces of decomposeGTConstantTime.
+// Rounding error of each coefficient is less than 5/8, so pieces are less than 5/8 of the sum of
+// absolute values of a column of gtDecompositionBasis, which is at most 7u + 3.
+const gtDecompositionBits = 65
+
+// gtDecompositionRounding[j] = round(gtDecompositionConstants[j] * 2^256 / q), so that round(k * n_j / q)
+// is approximated by (k * g_j + 2^255) >> 256 with fixed width arithmetic.
+var gtDecompositionRounding = func() (g [4][3]uint64) {
+	halfQ := new(big.Int).Rsh(q, 1)
+	for j := 0; j < 4; j++ {
+		t := new(big.Int).Lsh(gtDecompositionConstants[j], 256)
+		t.Add(t, halfQ).Quo(t, q)
+		for i := 0; i < 3; i++ {
+			g[j][i] = new(big.Int).Rsh(t, uint(64*i)).Uint64()
+		}
+	}
+	return g
+}()
+
+// gtDecompositionBasis128 is gtDecompositionBasis in two's complement modulo 2^128.
+var gtDecompositionBasis128 = func() (b [4][4][2]uint64) {
+	m := new(big.Int).Lsh(big.NewInt(1), 128)
+	for j := 0; j < 4; j++ {
+		for i := 0; i < 4; i++ {
+			t := new(big.Int).Mod(gtDecompositionBasis[j][i], m)
+			b[j][i] = [2]uint64{t.Uint64(), new(big.Int).Rsh(t, 64).Uint64()}
+		}
+	}
+	return b
+}()
+
+// decomposeGTConstantTime is decomposeGT with fixed width arithmetic for k < q. Pieces are small, so they
+// are computed modulo 2^128 and returned in two's complement.
+func decomposeGTConstantTime(k *[4]uint64) [4][2]uint64 {
+	out := [4][2]uint64{{k[0], k[1]}}
+	for j := 0; j < 4; j++ {
+		// r = k * g_j + 2^255, c_j is r >> 256 taken modulo 2^128
+		var r [7]uint64
+		g := &gtDecompositionRounding[j]
+		for i := 0; i < 4; i++ {
+			var carry uint64
+			for l := 0; l < 3; l++ {
+				hi, lo := bits.Mul64(k[i], g[l])
+				var c uint64
+				lo, c = bits.Add64(lo, r[i+l], 0)
+				hi += c
+				lo, c = bits.Add64(lo, carry, 0)
+				hi += c
+				r[i+l], carry = lo, hi
+			}
+			r[i+3] = carry
+		}
+		var c uint64
+		r[3], c = bits.Add64(r[3], 1<<63, 0)
+		r[4], c = bits.Add64(r[4], 0, c)
+		r[5], _ = bits.Add64(r[5], 0, c)
+		for i := 0; i < 4; i++ {
+			// out[i] -= c_j * b_ji modulo 2^128
+			b := &gtDecompositionBasis128[j][i]
+			hi, lo := bits.Mul64(r[4], b[0])
+			hi += r[4]*b[1] + r[5]*b[0]
+			var borrow uint64
+			out[i][0], borrow = bits.Sub64(out[i][0], lo, 0)
+			out[i][1], _ = bits.Sub64(out[i][1], hi, borrow)
+		}
+	}
+	return out
+}
+
+// decomposeGT decomposes a scalar k into k0, k1, k2, k3 such that
+// k = k0 + k1 * p + k2 * p^2 + k3 * p^3 mod q, using Babai rounding with the basis gtDecompositionBasis.
+func decomposeGT(s *big.Int) [4]*big.Int {
+	k := new(big.Int).Mod(s, q)
+	halfQ := new(big.Int).Rsh(q, 1)
+	out := [4]*big.Int{new(big.Int).Set(k), new(big.Int), new(big.Int), new(big.Int)}
+	c, t := new(big.Int), new(big.Int)
+	for j := 0; j < 4; j++ {
+		// c = round(k * n_j / q)
+		c.Mul(k, gtDecompositionConstants[j])
+		c.Add(c, halfQ)
+		c.Quo(c, q)
+		for i := 0; i < 4; i++ {
+			t.Mul(c, gtDecompositionBasis[j][i])
+			out[i].Sub(out[i], t)
+		}
+	}
+	return out
 }
 
 // Inverse inverses an element `a` and assigns the result to the element in first argument.
@@ -0,0 +1,310 @@
+package bn254
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"testing"
+)
+
+// (p^4 - p^2 + 1) / q
+var cofactorGT = bigFromHex("0x1baaa710b0759ad331ec15183177faf6c0eb522d5b122784e529a5861876f6b3b1b1355d189227d79581e16f3fd90c66b887d56d5095f23aaa441e3954bcf8adcc7b44c87cdbacff1154e7e1da014fd5abf5cc4f49c36d4e81bb482ccdf42b1")
+
+func (g *GT) randCyclotomic() *E {
+	// easy part of final exponentiation maps an element into cyclotomic subgroup
+	f, _ := new(fe12).rand(rand.Reader)
+	t0, t1 := new(fe12), new(fe12)
+	g.fp12.conjugate(t0, f)
+	g.fp12.inverse(t1, f)
+	g.fp12.mul(t0, t0, t1)
+	g.fp12.frobeniusMap(t1, t0, 2)
+	g.fp12.mul(t0, t0, t1)
+	return t0
+}
+
+func TestGTSerialization(t *testing.T) {
+	e := NewEngine()
+	gt := e.GT()
+	for i := 0; i < fuz; i++ {
+		a := e.AddPair(e.G1.rand(), e.G2.rand()).Result()
+		b, err := gt.FromBytes(gt.ToBytes(a))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !a.Equal(b) {
+			t.Fatal("bad serialization")
+		}
+	}
+}
+
+func TestGTSubgroupCheck(t *testing.T) {
+	e := NewEngine()
+	gt := e.GT()
+	if !gt.InCorrectSubgroup(gt.New()) {
+		t.Fatal("one is in correct subgroup")
+	}
+	if gt.InCorrectSubgroup(new(E)) {
+		t.Fatal("zero is not in correct subgroup")
+	}
+	for i := 0; i < fuz; i++ {
+		a := e.AddPair(e.G1.rand(), e.G2.rand()).Result()
+		if !gt.InCorrectSubgroup(a) {
+			t.Fatal("pairing result must be in correct subgroup")
+		}
+		// random element
+		b, _ := new(fe12).rand(rand.Reader)
+		if gt.fp12.isCyclotomic(b) || gt.InCorrectSubgroup(b) {
+			t.Fatal("random element is not expected to be in subgroup")
+		}
+		if _, err := gt.FromBytes(gt.ToBytes(b)); err == nil {
+			t.Fatal("element out of subgroup should be rejected")
+		}
+		if _, err := gt.FromBytesUnchecked(gt.ToBytes(b)); err != nil {
+			t.Fatal(err)
+		}
+		// element in cyclotomic subgroup but not in order q subgroup
+		c := gt.randCyclotomic()
+		if !gt.fp12.isCyclotomic(c) {
+			t.Fatal("element is expected to be in cyclotomic subgroup")
+		}
+		if gt.InCorrectSubgroup(c) {
+			t.Fatal("element is not expected to be in order q subgroup")
+		}
+		// (p^4 - p^2 + 1) / q multiple of cyclotomic element is in correct subgroup
+		gt.expPlain(c, c, cofactorGT)
+		if !gt.InCorrectSubgroup(c) {
+			t.Fatal("element is expected to be in order q subgroup")
+		}
+	}
+}
+
+func TestGTCompression(t *testing.T) {
+	e := NewEngine()
+	gt := e.GT()
+	one := gt.New()
+	compressed := gt.ToCompressed(one)
+	if len(compressed) != 192 {
+		t.Fatal("bad compressed length")
+	}
+	b, err := gt.FromCompressed(compressed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !b.IsOne() {
+		t.Fatal("bad compression of one")
+	}
+	for i := 0; i < fuz; i++ {
+		a := e.AddPair(e.G1.rand(), e.G2.rand()).Result()
+		b, err := gt.FromCompressed(gt.ToCompressed(a))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !a.Equal(b) {
+			t.Fatal("bad compression")
+		}
+		// all elements in cyclotomic subgroup
+		c := gt.randCyclotomic()
+		compressed := gt.ToCompressed(c)
+		d, err := gt.FromCompressedUnchecked(compressed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !c.Equal(d) {
+			t.Fatal("bad compression of cyclotomic element")
+		}
+		if _, err := gt.FromCompressed(compressed); err == nil {
+			t.Fatal("element out of subgroup should be rejected")
+		}
+	}
+}
+
+func TestGTDecomposition(t *testing.T) {
+	lambda := new(big.Int).Mod(pbig, q)
+	for i := 0; i < fuz; i++ {
+		for _, k := range []*big.Int{randScalar(q), big.NewInt(0), big.NewInt(1), new(big.Int).Sub(q, big.NewInt(1))} {
+			d := decomposeGT(k)
+			r, l := new(big.Int), big.NewInt(1)
+			for j := 0; j < 4; j++ {
+				if d[j].BitLen() > 66 {
+					t.Fatal("decomposed scalar is too large")
+				}
+				r.Add(r, new(big.Int).Mul(d[j], l))
+				l.Mul(l, lambda)
+			}
+			if r.Sub(r, k).Mod(r, q).Sign() != 0 {
+				t.Fatal("bad decomposition")
+			}
+		}
+	}
+}
+
+func TestGTDecompositionConstantTime(t *testing.T) {
+	lambda := new(big.Int).Mod(pbig, q)
+	m := new(big.Int).Lsh(big.NewInt(1), 128)
+	bound := new(big.Int).Lsh(big.NewInt(1), gtDecompositionBits)
+	for i := 0; i < fuz; i++ {
+		for _, k := range []*big.Int{randScalar(q), big.NewInt(0), big.NewInt(1), new(big.Int).Sub(q, big.NewInt(1)), new(big.Int).Rsh(q, 1)} {
+			var limbs [4]uint64
+			for j := 0; j < 4; j++ {
+				limbs[j] = new(big.Int).Rsh(k, uint(64*j)).Uint64()
+			}
+			d := decomposeGTConstantTime(&limbs)
+			r, l := new(big.Int), big.NewInt(1)
+			for j := 0; j < 4; j++ {
+				// two's complement modulo 2^128 to signed integer
+				kj := new(big.Int).SetUint64(d[j][1])
+				kj.Lsh(kj, 64).Add(kj, new(big.Int).SetUint64(d[j][0]))
+				if d[j][1]>>63 == 1 {
+					kj.Sub(kj, m)
+				}
+				if new(big.Int).Abs(kj).Cmp(bound) >= 0 {
+					t.Fatal("decomposed scalar is too large")
+				}
+				r.Add(r, new(big.Int).Mul(kj, l))
+				l.Mul(l, lambda)
+			}
+			if r.Sub(r, k).Mod(r, q).Sign() != 0 {
+				t.Fatal("bad constant time decomposition")
+			}
+		}
+	}
+}
+
+func TestGTExp(t *testing.T) {
+	e := NewEngine()
+	gt := e.GT()
+	for i := 0; i < fuz; i++ {
+		a := e.AddPair(e.G1.rand(), e.G2.rand()).Result()
+		scalars := []*big.Int{
+			randScalar(q),
+			randScalar(new(big.Int).Lsh(q, 8)),
+			big.NewInt(0),
+			big.NewInt(1),
+			new(big.Int).Sub(q, big.NewInt(1)),
+			new(big.Int).Set(q),
+		}
+		for _, s := range scalars {
+			expected, r0, r1 := new(E), new(E), new(E)
+			gt.expPlain(expected, a, s)
+			gt.Exp(r0, a, s)
+			if !r0.Equal(expected) {
+				t.Fatal("bad exponentiation")
+			}
+			gt.ExpConstantTime(r1, a, new(Fr).SetBig(s))
+			if !r1.Equal(expected) {
+				t.Fatal("bad constant time exponentiation")
+			}
+		}
+		// a^(-s) * a^s == 1
+		s := randScalar(q)
+		r0, r1 := new(E), new(E)
+		gt.Exp(r0, a, s)
+		gt.Exp(r1, a, new(big.Int).Neg(s))
+		gt.Mul(r0, r0, r1)
+		if !r0.IsOne() {
+			t.Fatal("bad exponentiation by negative scalar")
+		}
+	}
+}
+
+// expPlain exponentiates with generic fp12 square and multiply
+// so that it shares no code with the cyclotomic exponentiations under test.
+func (g *GT) expPlain(c, a *E, s *big.Int) {
+	g.fp12.exp(c, a, new(big.Int).Abs(s))
+	if s.Sign() < 0 {
+		g.fp12.inverse(c, c)
+	}
+}
+
+func TestGTMultiExp(t *testing.T) {
+	e := NewEngine()
+	gt := e.GT()
+	a := e.AddPair(e.G1.rand(), e.G2.rand()).Result()
+	// 31, 32 and 33 are around the switch from interleaved to bucket method
+	for _, n := range []int{0, 1, 2, 5, 31, 32, 33, 70} {
+		elems, scalars := make([]*E, n), randScalars(q, n)
+		if n > 1 {
+			scalars[1].Neg(scalars[1])
+		}
+		expected, t0 := gt.New(), new(E)
+		for i := 0; i < n; i++ {
+			// a^(i + 1) keeps elements distinct without a pairing per element
+			elems[i] = new(E)
+			gt.expPlain(elems[i], a, big.NewInt(int64(i+1)))
+			gt.expPlain(t0, elems[i], scalars[i])
+			gt.fp12.mul(expected, expected, t0)
+		}
+		copies := make([]*big.Int, n)
+		for i := 0; i < n; i++ {
+			copies[i] = new(big.Int).Set(scalars[i])
+		}
+		r := new(E)
+		if _, err := gt.MultiExp(r, elems, scalars); err != nil {
+			t.Fatal(err)
+		}
+		if !r.Equal(expected) {
+			t.Fatalf("bad multi exponentiation, size %d", n)
+		}
+		for i := 0; i < n; i++ {
+			if scalars[i].Cmp(copies[i]) != 0 {
+				t.Fatal("scalars should not be modified")
+			}
+		}
+	}
+	if _, err := gt.MultiExp(new(E), []*E{a}, nil); err == nil {
+		t.Fatal("length mismatch should be rejected")
+	}
+}
+
+func BenchmarkGTMultiExp(t *testing.B) {
+	e := NewEngine()
+	gt := e.GT()
+	a := e.AddPair(e.G1.One(), e.G2.One()).Result()
+	for _, n := range []int{2, 64} {
+		elems, scalars := make([]*E, n), randScalars(q, n)
+		for i := 0; i < n; i++ {
+			elems[i] = new(E)
+			gt.Exp(elems[i], a, big.NewInt(int64(i+1)))
+		}
+		r := new(E)
+		t.Run(fmt.Sprintf("%d", n), func(t *testing.B) {
+			for i := 0; i < t.N; i++ {
+				_, _ = gt.MultiExp(r, elems, scalars)
+			}
+		})
+	}
+}
+
+func BenchmarkGTExp(t *testing.B) {
+	e := NewEngine()
+	gt := e.GT()
+	a := e.AddPair(e.G1.One(), e.G2.One()).Result()
+	s := randScalar(q)
+	c := new(E)
+	t.ResetTimer()
+	for i := 0; i < t.N; i++ {
+		gt.Exp(c, a, s)
+	}
+}
+
+func BenchmarkGTExpConstantTime(t *testing.B) {
+	e := NewEngine()
+	gt := e.GT()
+	a := e.AddPair(e.G1.One(), e.G2.One()).Result()
+	s, _ := new(Fr).Rand(rand.Reader)
+	c := new(E)
+	t.ResetTimer()
+	for i := 0; i < t.N; i++ {
+		gt.ExpConstantTime(c, a, s)
+	}
+}
+
+func BenchmarkGTSubgroupCheck(t *testing.B) {
+	e := NewEngine()
+	gt := e.GT()
+	a := e.AddPair(e.G1.One(), e.G2.One()).Result()
+	t.ResetTimer()
+	for i := 0; i < t.N; i++ {
+		_ = gt.InCorrectSubgroup(a)
+	}
+}
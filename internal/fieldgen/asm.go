@@ -0,0 +1,728 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// asmWriter collects instructions and aligns operands of consecutive instructions.
+type asmWriter struct {
+	buf   bytes.Buffer
+	block [][2]string
+}
+
+func (w *asmWriter) flush() {
+	width := 0
+	for _, ins := range w.block {
+		if len(ins[0]) > width {
+			width = len(ins[0])
+		}
+	}
+	for _, ins := range w.block {
+		if ins[1] == "" {
+			fmt.Fprintf(&w.buf, "\t%s\n", ins[0])
+			continue
+		}
+		fmt.Fprintf(&w.buf, "\t%-*s %s\n", width, ins[0], ins[1])
+	}
+	w.block = w.block[:0]
+}
+
+func (w *asmWriter) i(op string, args ...string) {
+	w.block = append(w.block, [2]string{op, strings.Join(args, ", ")})
+}
+
+func (w *asmWriter) line(format string, a ...interface{}) {
+	w.flush()
+	fmt.Fprintf(&w.buf, format+"\n", a...)
+}
+
+func (w *asmWriter) comment(format string, a ...interface{}) {
+	w.line("\t// "+format, a...)
+}
+
+func (w *asmWriter) nl() {
+	w.line("")
+}
+
+func (w *asmWriter) bytes() []byte {
+	w.flush()
+	return w.buf.Bytes()
+}
+
+// mem returns memory operand of ith limb at address in register r.
+func mem(r string, i int) string {
+	if i == 0 {
+		return "(" + r + ")"
+	}
+	return fmt.Sprintf("%d(%s)", 8*i, r)
+}
+
+func imm(v int) string {
+	return fmt.Sprintf("$0x%02x", v)
+}
+
+type asmGen struct {
+	*field
+	asmWriter
+}
+
+func (g *asmGen) mod(i int) string {
+	return fmt.Sprintf("·%s+%d(SB)", g.modulusVar(), 8*i)
+}
+
+func (g *asmGen) inp() string {
+	return fmt.Sprintf("·%s+0(SB)", g.inpVar())
+}
+
+func (g *asmGen) text(op, signature string, frame int) {
+	g.line("// func %s%s", g.name(op), signature)
+	g.line("TEXT ·%s(SB), NOSPLIT, $0-%d", g.name(op), frame)
+}
+
+func (g *asmGen) ret() {
+	g.i("RET")
+	g.nl()
+}
+
+// trialSub writes r - p to t and propagates the borrow into carry, so that a following
+// storeSelected keeps r - p only if the subtraction does not borrow.
+func (g *asmGen) trialSub(r, t []string, carry string) {
+	for i := 0; i < 4; i++ {
+		g.i("MOVQ", r[i], t[i])
+		if i == 0 {
+			g.i("SUBQ", g.mod(i), t[i])
+		} else {
+			g.i("SBBQ", g.mod(i), t[i])
+		}
+	}
+	g.i("SBBQ", imm(0), carry)
+}
+
+func (g *asmGen) storeSelected(r, t []string, out string) {
+	for i := 0; i < 4; i++ {
+		g.i("CMOVQCC", t[i], r[i])
+		g.i("MOVQ", r[i], mem(out, i))
+	}
+}
+
+func (g *asmGen) store(r []string, out string) {
+	for i := 0; i < 4; i++ {
+		g.i("MOVQ", r[i], mem(out, i))
+	}
+}
+
+func (g *asmGen) add(assign bool) {
+	r := []string{"CX", "DX", "R8", "R9"}
+	t := []string{"R10", "R11", "R12", "R13"}
+	if assign {
+		g.text("addAssign", "(a *[4]uint64, b *[4]uint64)", 16)
+		g.i("MOVQ", "a+0(FP)", "DI")
+		g.i("MOVQ", "b+8(FP)", "SI")
+	} else {
+		g.text("add", "(c *[4]uint64, a *[4]uint64, b *[4]uint64)", 24)
+		g.i("MOVQ", "a+8(FP)", "DI")
+		g.i("MOVQ", "b+16(FP)", "SI")
+	}
+	g.i("XORQ", "AX", "AX")
+	g.nl()
+	for i := 0; i < 4; i++ {
+		g.i("MOVQ", mem("DI", i), r[i])
+		if i == 0 {
+			g.i("ADDQ", mem("SI", i), r[i])
+		} else {
+			g.i("ADCQ", mem("SI", i), r[i])
+		}
+	}
+	g.i("ADCQ", imm(0), "AX")
+	g.nl()
+	g.trialSub(r, t, "AX")
+	g.nl()
+	if !assign {
+		g.i("MOVQ", "c+0(FP)", "DI")
+	}
+	g.storeSelected(r, t, "DI")
+	g.ret()
+}
+
+func (g *asmGen) laddAssign() {
+	r := []string{"CX", "DX", "R8", "R9"}
+	g.text("laddAssign", "(a *[4]uint64, b *[4]uint64)", 16)
+	g.i("MOVQ", "a+0(FP)", "DI")
+	g.i("MOVQ", "b+8(FP)", "SI")
+	g.nl()
+	for i := 0; i < 4; i++ {
+		g.i("MOVQ", mem("DI", i), r[i])
+		if i == 0 {
+			g.i("ADDQ", mem("SI", i), r[i])
+		} else {
+			g.i("ADCQ", mem("SI", i), r[i])
+		}
+	}
+	g.nl()
+	g.store(r, "DI")
+	g.ret()
+}
+
+func (g *asmGen) double(assign bool) {
+	r := []string{"CX", "DX", "SI", "R8"}
+	t := []string{"R9", "R10", "R11", "R12"}
+	if assign {
+		g.text("doubleAssign", "(a *[4]uint64)", 8)
+		g.i("MOVQ", "a+0(FP)", "DI")
+	} else {
+		g.text("double", "(c *[4]uint64, a *[4]uint64)", 16)
+		g.i("MOVQ", "a+8(FP)", "DI")
+	}
+	g.i("XORQ", "AX", "AX")
+	for i := 0; i < 4; i++ {
+		g.i("MOVQ", mem("DI", i), r[i])
+		if i == 0 {
+			g.i("ADDQ", r[i], r[i])
+		} else {
+			g.i("ADCQ", r[i], r[i])
+		}
+	}
+	g.i("ADCQ", imm(0), "AX")
+	g.nl()
+	g.trialSub(r, t, "AX")
+	g.nl()
+	if !assign {
+		g.i("MOVQ", "c+0(FP)", "DI")
+	}
+	g.storeSelected(r, t, "DI")
+	g.ret()
+}
+
+func (g *asmGen) sub(assign bool) {
+	r := []string{"CX", "DX", "R8", "R9"}
+	m := []string{"SI", "R10", "R11", "R12"}
+	if assign {
+		g.text("subAssign", "(a *[4]uint64, b *[4]uint64)", 16)
+		g.i("MOVQ", "a+0(FP)", "DI")
+		g.i("MOVQ", "b+8(FP)", "SI")
+	} else {
+		g.text("sub", "(c *[4]uint64, a *[4]uint64, b *[4]uint64)", 24)
+		g.i("MOVQ", "a+8(FP)", "DI")
+		g.i("MOVQ", "b+16(FP)", "SI")
+	}
+	g.i("XORQ", "AX", "AX")
+	for i := 0; i < 4; i++ {
+		g.i("MOVQ", mem("DI", i), r[i])
+		if i == 0 {
+			g.i("SUBQ", mem("SI", i), r[i])
+		} else {
+			g.i("SBBQ", mem("SI", i), r[i])
+		}
+	}
+	g.nl()
+	// add modulus back if subtraction borrows
+	for i := 0; i < 4; i++ {
+		g.i("MOVQ", g.mod(i), m[i])
+		g.i("CMOVQCC", "AX", m[i])
+	}
+	g.nl()
+	if !assign {
+		g.i("MOVQ", "c+0(FP)", "DI")
+	}
+	for i := 0; i < 4; i++ {
+		if i == 0 {
+			g.i("ADDQ", m[i], r[i])
+		} else {
+			g.i("ADCQ", m[i], r[i])
+		}
+		g.i("MOVQ", r[i], mem("DI", i))
+	}
+	g.ret()
+}
+
+func (g *asmGen) lsubAssign() {
+	r := []string{"CX", "DX", "R8", "R9"}
+	g.text("lsubAssign", "(a *[4]uint64, b *[4]uint64) uint64", 24)
+	g.i("MOVQ", "a+0(FP)", "DI")
+	g.i("MOVQ", "b+8(FP)", "SI")
+	g.i("XORQ", "AX", "AX")
+	g.nl()
+	for i := 0; i < 4; i++ {
+		g.i("MOVQ", mem("DI", i), r[i])
+		if i == 0 {
+			g.i("SUBQ", mem("SI", i), r[i])
+		} else {
+			g.i("SBBQ", mem("SI", i), r[i])
+		}
+	}
+	g.i("ADCQ", imm(0), "AX")
+	g.nl()
+	g.store(r, "DI")
+	g.i("MOVQ", "AX", "ret+16(FP)")
+	g.ret()
+}
+
+// neg selects zero with a conditional move rather than a branch when a is zero,
+// so that p is never returned.
+func (g *asmGen) neg() {
+	r := []string{"CX", "DX", "SI", "R8"}
+	g.text("neg", "(c *[4]uint64, a *[4]uint64)", 16)
+	g.i("MOVQ", "a+8(FP)", "DI")
+	g.i("XORQ", "R9", "R9")
+	g.nl()
+	for i := 0; i < 4; i++ {
+		g.i("MOVQ", g.mod(i), r[i])
+		if i == 0 {
+			g.i("SUBQ", mem("DI", i), r[i])
+		} else {
+			g.i("SBBQ", mem("DI", i), r[i])
+		}
+	}
+	g.nl()
+	g.i("MOVQ", mem("DI", 0), "AX")
+	for i := 1; i < 4; i++ {
+		g.i("ORQ", mem("DI", i), "AX")
+	}
+	for i := 0; i < 4; i++ {
+		g.i("CMOVQEQ", "R9", r[i])
+	}
+	g.nl()
+	g.i("MOVQ", "c+0(FP)", "DI")
+	g.store(r, "DI")
+	g.ret()
+}
+
+// Double width products are kept in these registers before the Montgomery reduction.
+var (
+	wideNoADX = []string{"R8", "R9", "R10", "R11", "R12", "R13", "R14", "BX"}
+	wideADX   = []string{"CX", "R8", "R9", "R10", "R11", "R12", "R13", "DI"}
+)
+
+func (g *asmGen) mulNoADX() {
+	g.text("mulNoADX", "(c *[4]uint64, a *[4]uint64, b *[4]uint64)", 24)
+	g.productNoADX()
+	g.montRedNoADX()
+}
+
+// productNoADX writes double width product of a and b into wideNoADX registers.
+func (g *asmGen) productNoADX() {
+	w := wideNoADX
+	g.i("MOVQ", "a+8(FP)", "DI")
+	g.i("MOVQ", "b+16(FP)", "SI")
+	for i := 2; i < 7; i++ {
+		g.i("MOVQ", imm(0), w[i])
+	}
+	g.nl()
+	for i := 0; i < 4; i++ {
+		g.comment("| a%d @ CX", i)
+		g.i("MOVQ", mem("DI", i), "CX")
+		if i > 0 {
+			g.i("MOVQ", imm(0), "BX")
+		}
+		g.nl()
+		for j := 0; j < 4; j++ {
+			g.comment("| a%d * b%d", i, j)
+			g.i("MOVQ", mem("SI", j), "AX")
+			g.i("MULQ", "CX")
+			k := i + j
+			if i == 0 {
+				if j == 0 {
+					g.i("MOVQ", "AX", w[0])
+					g.i("MOVQ", "DX", w[1])
+				} else {
+					g.i("ADDQ", "AX", w[k])
+					g.i("ADCQ", "DX", w[k+1])
+				}
+				g.nl()
+				continue
+			}
+			g.i("ADDQ", "AX", w[k])
+			g.i("ADCQ", "DX", w[k+1])
+			switch j {
+			case 0:
+				g.i("ADCQ", imm(0), w[k+2])
+				g.i("ADCQ", imm(0), "BX")
+			case 1:
+				g.i("ADCQ", "BX", w[k+2])
+				g.i("MOVQ", imm(0), "BX")
+				g.i("ADCQ", imm(0), "BX")
+			case 2:
+				// top word of the last row is BX itself
+				if k+2 == 7 {
+					g.i("ADCQ", imm(0), "BX")
+				} else {
+					g.i("ADCQ", "BX", w[k+2])
+				}
+			}
+			g.nl()
+		}
+	}
+}
+
+func (g *asmGen) squareNoADX() {
+	w := wideNoADX
+	g.text("squareNoADX", "(c *[4]uint64, a *[4]uint64)", 16)
+	g.i("MOVQ", "a+8(FP)", "DI")
+	g.nl()
+	g.comment("| cross products")
+	g.i("MOVQ", mem("DI", 0), "CX")
+	g.i("MOVQ", mem("DI", 1), "AX")
+	g.i("MULQ", "CX")
+	g.i("MOVQ", "AX", w[1])
+	g.i("MOVQ", "DX", w[2])
+	for j := 2; j < 4; j++ {
+		g.i("MOVQ", mem("DI", j), "AX")
+		g.i("MULQ", "CX")
+		g.i("ADDQ", "AX", w[j])
+		g.i("ADCQ", imm(0), "DX")
+		g.i("MOVQ", "DX", w[j+1])
+	}
+	g.i("MOVQ", mem("DI", 1), "CX")
+	g.i("MOVQ", mem("DI", 2), "AX")
+	g.i("MULQ", "CX")
+	g.i("ADDQ", "AX", w[3])
+	g.i("ADCQ", "DX", w[4])
+	g.i("MOVQ", imm(0), w[5])
+	g.i("ADCQ", imm(0), w[5])
+	g.i("MOVQ", mem("DI", 3), "AX")
+	g.i("MULQ", "CX")
+	g.i("ADDQ", "AX", w[4])
+	g.i("ADCQ", "DX", w[5])
+	g.i("MOVQ", mem("DI", 2), "CX")
+	g.i("MOVQ", mem("DI", 3), "AX")
+	g.i("MULQ", "CX")
+	g.i("ADDQ", "AX", w[5])
+	g.i("ADCQ", imm(0), "DX")
+	g.i("MOVQ", "DX", w[6])
+	g.nl()
+	g.comment("| double cross products")
+	g.i("MOVQ", imm(0), w[7])
+	for i := 1; i < 7; i++ {
+		if i == 1 {
+			g.i("ADDQ", w[i], w[i])
+		} else {
+			g.i("ADCQ", w[i], w[i])
+		}
+	}
+	g.i("ADCQ", imm(0), w[7])
+	g.nl()
+	g.comment("| diagonal")
+	// MULQ clobbers flags, carry of each diagonal term is kept in CX
+	for i := 0; i < 4; i++ {
+		g.i("MOVQ", mem("DI", i), "AX")
+		g.i("MULQ", "AX")
+		switch i {
+		case 0:
+			g.i("MOVQ", "AX", w[0])
+			g.i("MOVQ", "DX", "CX")
+		case 3:
+			g.i("ADDQ", "CX", w[5])
+			g.i("ADCQ", "AX", w[6])
+			g.i("ADCQ", "DX", w[7])
+		default:
+			g.i("ADDQ", "CX", w[2*i-1])
+			g.i("ADCQ", "AX", w[2*i])
+			g.i("ADCQ", imm(0), "DX")
+			g.i("MOVQ", "DX", "CX")
+		}
+	}
+	g.nl()
+	g.montRedNoADX()
+}
+
+// montRedNoADX reduces double width value in wideNoADX registers and writes the result to c.
+func (g *asmGen) montRedNoADX() {
+	w := wideNoADX
+	g.comment("| montgomery reduction")
+	for i := 0; i < 4; i++ {
+		g.comment("| u%d = w%d * inp", i, i)
+		g.i("MOVQ", w[i], "AX")
+		g.i("MULQ", g.inp())
+		g.i("MOVQ", "AX", "DI")
+		g.i("MOVQ", imm(0), "CX")
+		g.nl()
+		g.i("MOVQ", g.mod(0), "AX")
+		g.i("MULQ", "DI")
+		g.i("ADDQ", "AX", w[i])
+		g.i("ADCQ", "DX", "CX")
+		for j := 1; j < 3; j++ {
+			g.i("MOVQ", g.mod(j), "AX")
+			g.i("MULQ", "DI")
+			g.i("ADDQ", "AX", w[i+j])
+			g.i("ADCQ", imm(0), "DX")
+			g.i("ADDQ", "CX", w[i+j])
+			g.i("MOVQ", imm(0), "CX")
+			g.i("ADCQ", "DX", "CX")
+		}
+		g.i("MOVQ", g.mod(3), "AX")
+		g.i("MULQ", "DI")
+		g.i("ADDQ", "AX", w[i+3])
+		// w0 is zero after the first round and carries the overflow of the top word
+		if i == 0 {
+			g.i("ADCQ", imm(0), "DX")
+			g.i("ADDQ", "CX", w[3])
+			g.i("ADCQ", "DX", w[4])
+			g.i("ADCQ", imm(0), w[0])
+		} else {
+			g.i("ADCQ", "DX", w[0])
+			g.i("ADDQ", "CX", w[i+3])
+			g.i("ADCQ", w[0], w[i+4])
+			g.i("MOVQ", imm(0), w[0])
+			g.i("ADCQ", imm(0), w[0])
+		}
+		g.nl()
+	}
+	g.comment("| modular reduction")
+	t := []string{"SI", "R9", "R10", "R11"}
+	g.trialSub(w[4:], t, w[0])
+	g.nl()
+	g.i("MOVQ", "c+0(FP)", w[0])
+	g.storeSelected(w[4:], t, w[0])
+	g.ret()
+}
+
+func (g *asmGen) mulADX() {
+	w := wideADX
+	g.text("mulADX", "(c *[4]uint64, a *[4]uint64, b *[4]uint64)", 24)
+	g.i("MOVQ", "a+8(FP)", "DI")
+	g.i("MOVQ", "b+16(FP)", "SI")
+	g.i("XORQ", "AX", "AX")
+	g.nl()
+	g.comment("| a0 @ DX")
+	g.i("MOVQ", mem("DI", 0), "DX")
+	g.i("MULXQ", mem("SI", 0), w[0], w[1])
+	for j := 1; j < 4; j++ {
+		g.i("MULXQ", mem("SI", j), "AX", w[j+1])
+		g.i("ADCXQ", "AX", w[j])
+	}
+	g.i("ADCQ", imm(0), w[4])
+	g.nl()
+	for i := 1; i < 4; i++ {
+		g.comment("| a%d @ DX", i)
+		// top word of the last row is the pointer register, a3 is loaded before it is cleared
+		g.i("MOVQ", mem("DI", i), "DX")
+		g.i("XORQ", w[i+4], w[i+4])
+		for j := 0; j < 4; j++ {
+			g.i("MULXQ", mem("SI", j), "AX", "BX")
+			g.i("ADOXQ", "AX", w[i+j])
+			if j < 3 {
+				g.i("ADCXQ", "BX", w[i+j+1])
+			} else {
+				g.i("ADOXQ", w[i+4], w[i+4])
+				g.i("ADCXQ", "BX", w[i+4])
+			}
+		}
+		g.nl()
+	}
+	g.montRedADX()
+}
+
+func (g *asmGen) squareADX() {
+	w := wideADX
+	g.text("squareADX", "(c *[4]uint64, a *[4]uint64)", 16)
+	g.i("MOVQ", "a+8(FP)", "SI")
+	g.nl()
+	g.comment("| cross products")
+	g.i("MOVQ", mem("SI", 0), "DX")
+	g.i("MULXQ", mem("SI", 1), w[1], w[2])
+	g.i("MULXQ", mem("SI", 2), "AX", w[3])
+	g.i("ADDQ", "AX", w[2])
+	g.i("MULXQ", mem("SI", 3), "AX", w[4])
+	g.i("ADCQ", "AX", w[3])
+	g.i("ADCQ", imm(0), w[4])
+	g.i("MOVQ", mem("SI", 1), "DX")
+	g.i("XORQ", w[5], w[5])
+	g.i("MULXQ", mem("SI", 2), "AX", "BX")
+	g.i("ADOXQ", "AX", w[3])
+	g.i("ADCXQ", "BX", w[4])
+	g.i("MULXQ", mem("SI", 3), "AX", "BX")
+	g.i("ADOXQ", "AX", w[4])
+	g.i("ADCXQ", "BX", w[5])
+	g.i("MOVQ", imm(0), "AX")
+	g.i("ADOXQ", "AX", w[5])
+	g.i("MOVQ", mem("SI", 2), "DX")
+	g.i("MULXQ", mem("SI", 3), "AX", w[6])
+	g.i("ADDQ", "AX", w[5])
+	g.i("ADCQ", imm(0), w[6])
+	g.nl()
+	g.comment("| double cross products")
+	g.i("XORQ", w[7], w[7])
+	for i := 1; i < 7; i++ {
+		if i == 1 {
+			g.i("ADDQ", w[i], w[i])
+		} else {
+			g.i("ADCQ", w[i], w[i])
+		}
+	}
+	g.i("ADCQ", imm(0), w[7])
+	g.nl()
+	g.comment("| diagonal")
+	// MULXQ leaves flags untouched so that diagonal terms are added in a single carry chain
+	for i := 0; i < 4; i++ {
+		g.i("MOVQ", mem("SI", i), "DX")
+		if i == 0 {
+			g.i("MULXQ", "DX", w[0], "AX")
+			g.i("ADDQ", "AX", w[1])
+			continue
+		}
+		g.i("MULXQ", "DX", "AX", "BX")
+		g.i("ADCQ", "AX", w[2*i])
+		g.i("ADCQ", "BX", w[2*i+1])
+	}
+	g.nl()
+	g.montRedADX()
+}
+
+// montRedADX reduces double width value in wideADX registers and writes the result to c.
+func (g *asmGen) montRedADX() {
+	w := wideADX
+	g.comment("| montgomery reduction")
+	for i := 0; i < 4; i++ {
+		g.comment("| u%d = w%d * inp", i, i)
+		g.i("XORQ", "AX", "AX")
+		g.i("MOVQ", w[i], "DX")
+		g.i("MULXQ", g.inp(), "DX", "BX")
+		for j := 0; j < 4; j++ {
+			g.i("MULXQ", g.mod(j), "AX", "BX")
+			g.i("ADOXQ", "AX", w[i+j])
+			g.i("ADCXQ", "BX", w[i+j+1])
+		}
+		// reduced word is zero and carries the overflow of the top word to the next round
+		carry := w[0]
+		if i > 0 {
+			carry = w[i-1]
+		}
+		g.i("ADOXQ", carry, w[i+4])
+		g.i("ADCXQ", w[i], w[i])
+		g.i("MOVQ", imm(0), "AX")
+		g.i("ADOXQ", "AX", w[i])
+		g.nl()
+	}
+	g.comment("| modular reduction")
+	t := []string{"CX", "AX", "BX", "SI"}
+	g.trialSub(w[4:], t, w[3])
+	g.nl()
+	g.i("MOVQ", "c+0(FP)", w[3])
+	g.storeSelected(w[4:], t, w[3])
+	g.ret()
+}
+
+func (f *field) asm() []byte {
+	g := &asmGen{field: f}
+	g.line("//go:build amd64 && !generic")
+	g.line("// +build amd64,!generic")
+	g.nl()
+	g.line("// Code generated by %s. DO NOT EDIT.", f.command())
+	g.nl()
+	g.line("#include \"textflag.h\"")
+	g.nl()
+	g.add(false)
+	g.add(true)
+	g.laddAssign()
+	g.double(false)
+	g.double(true)
+	g.sub(false)
+	g.sub(true)
+	g.lsubAssign()
+	g.neg()
+	g.mulADX()
+	g.squareADX()
+	g.mulNoADX()
+	g.squareNoADX()
+	return append(bytes.TrimRight(g.bytes(), "\n"), '\n')
+}
+
+func (g *asmGen) wmul() {
+	g.text("wmul", "(c *[8]uint64, a *[4]uint64, b *[4]uint64)", 24)
+	g.productNoADX()
+	g.i("MOVQ", "c+0(FP)", "DI")
+	for i := 0; i < 8; i++ {
+		g.i("MOVQ", wideNoADX[i], mem("DI", i))
+	}
+	g.ret()
+}
+
+func (g *asmGen) montRed() {
+	g.text("montRed", "(c *[4]uint64, a *[8]uint64)", 16)
+	g.i("MOVQ", "a+8(FP)", "SI")
+	for i := 0; i < 8; i++ {
+		g.i("MOVQ", mem("SI", i), wideNoADX[i])
+	}
+	g.nl()
+	g.montRedNoADX()
+}
+
+// wideAddSub adds or subtracts wide elements modulo p * 2^256, only the upper half is reduced.
+func (g *asmGen) wideAddSub(subtract bool) {
+	op, opc := "ADDQ", "ADCQ"
+	if subtract {
+		op, opc = "SUBQ", "SBBQ"
+		g.text("wsub", "(c *[8]uint64, a *[8]uint64, b *[8]uint64)", 24)
+	} else {
+		g.text("wadd", "(c *[8]uint64, a *[8]uint64, b *[8]uint64)", 24)
+	}
+	r := []string{"CX", "DX", "R8", "R9"}
+	t := []string{"R10", "R11", "R12", "R13"}
+	g.i("MOVQ", "a+8(FP)", "DI")
+	g.i("MOVQ", "b+16(FP)", "SI")
+	g.i("MOVQ", "c+0(FP)", "BX")
+	g.i("XORQ", "AX", "AX")
+	g.nl()
+	g.comment("| lower half is not affected by reduction")
+	for i := 0; i < 8; i++ {
+		reg := r[i%4]
+		g.i("MOVQ", mem("DI", i), reg)
+		if i == 0 {
+			g.i(op, mem("SI", i), reg)
+		} else {
+			g.i(opc, mem("SI", i), reg)
+		}
+		if i < 4 {
+			g.i("MOVQ", reg, mem("BX", i))
+		}
+	}
+	if subtract {
+		g.nl()
+		g.comment("| add p * 2^256 if subtraction borrows")
+		for i := 0; i < 4; i++ {
+			g.i("MOVQ", g.mod(i), t[i])
+			g.i("CMOVQCC", "AX", t[i])
+		}
+		g.nl()
+		for i := 0; i < 4; i++ {
+			if i == 0 {
+				g.i("ADDQ", t[i], r[i])
+			} else {
+				g.i("ADCQ", t[i], r[i])
+			}
+			g.i("MOVQ", r[i], mem("BX", i+4))
+		}
+		g.ret()
+		return
+	}
+	g.i("ADCQ", imm(0), "AX")
+	g.nl()
+	g.comment("| subtract p * 2^256 if it does not borrow")
+	g.trialSub(r, t, "AX")
+	g.nl()
+	for i := 0; i < 4; i++ {
+		g.i("CMOVQCC", t[i], r[i])
+		g.i("MOVQ", r[i], mem("BX", i+4))
+	}
+	g.ret()
+}
+
+func (f *field) wideAsm() []byte {
+	g := &asmGen{field: f}
+	g.line("//go:build amd64 && !generic")
+	g.line("// +build amd64,!generic")
+	g.nl()
+	g.line("// Code generated by %s. DO NOT EDIT.", f.command())
+	g.nl()
+	g.line("// Double width arithmetic for lazy reduction. Wide elements are 512 bit integers in [0, p * 2^256).")
+	g.nl()
+	g.line("#include \"textflag.h\"")
+	g.nl()
+	g.wmul()
+	g.montRed()
+	g.wideAddSub(false)
+	g.wideAddSub(true)
+	return append(bytes.TrimRight(g.bytes(), "\n"), '\n')
+}
@@ -0,0 +1,320 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"text/template"
+)
+
+func (f *field) execute(t *template.Template) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, f); err != nil {
+		return nil, err
+	}
+	out, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated source: %v", err)
+	}
+	return out, nil
+}
+
+func (f *field) Command() string {
+	return f.command()
+}
+
+func (f *field) Package() string {
+	return f.pkg
+}
+
+func (f *field) Type() string {
+	return f.typ
+}
+
+func (f *field) Name(op string) string {
+	return f.name(op)
+}
+
+func (f *field) P(i int) string {
+	return fmt.Sprintf("%#016x", f.limbs[i])
+}
+
+func (f *field) Inp() string {
+	return fmt.Sprintf("%#016x", f.inp)
+}
+
+func (f *field) decl() ([]byte, error) {
+	return f.execute(declTemplate)
+}
+
+func (f *field) fallback() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(f.mulFallback())
+	t := template.Must(fallbackTemplate.Clone())
+	template.Must(t.New("mul").Parse(buf.String()))
+	return f.execute(t)
+}
+
+var declTemplate = template.Must(template.New("decl").Parse(`//go:build amd64 && !generic
+// +build amd64,!generic
+
+// Code generated by {{ .Command }}. DO NOT EDIT.
+
+package {{ .Package }}
+
+import "golang.org/x/sys/cpu"
+
+// ADX and BMI2 variants are the defaults, init falls back to the others where they are not available.
+func init() {
+	if !cpu.X86.HasADX || !cpu.X86.HasBMI2 {
+		{{ .Name "mul" }} = {{ .Name "mulNoADX" }}
+		{{ .Name "square" }} = {{ .Name "squareNoADX" }}
+	}
+}
+
+var {{ .Name "mul" }} func(c, a, b *{{ .Type }}) = {{ .Name "mulADX" }}
+
+var {{ .Name "square" }} func(c, a *{{ .Type }}) = {{ .Name "squareADX" }}
+
+//go:noescape
+func {{ .Name "add" }}(c, a, b *{{ .Type }})
+
+//go:noescape
+func {{ .Name "addAssign" }}(a, b *{{ .Type }})
+
+//go:noescape
+func {{ .Name "laddAssign" }}(a, b *{{ .Type }})
+
+//go:noescape
+func {{ .Name "sub" }}(c, a, b *{{ .Type }})
+
+//go:noescape
+func {{ .Name "subAssign" }}(a, b *{{ .Type }})
+
+//go:noescape
+func {{ .Name "lsubAssign" }}(a, b *{{ .Type }}) uint64
+
+//go:noescape
+func {{ .Name "neg" }}(c, a *{{ .Type }})
+
+//go:noescape
+func {{ .Name "double" }}(c, a *{{ .Type }})
+
+//go:noescape
+func {{ .Name "doubleAssign" }}(a *{{ .Type }})
+
+//go:noescape
+func {{ .Name "mulNoADX" }}(c, a, b *{{ .Type }})
+
+//go:noescape
+func {{ .Name "mulADX" }}(c, a, b *{{ .Type }})
+
+//go:noescape
+func {{ .Name "squareNoADX" }}(c, a *{{ .Type }})
+
+//go:noescape
+func {{ .Name "squareADX" }}(c, a *{{ .Type }})
+`))
+
+var fallbackTemplate = template.Must(template.New("fallback").Parse(`//go:build !amd64 || generic
+// +build !amd64 generic
+
+// Code generated by {{ .Command }}. DO NOT EDIT.
+
+package {{ .Package }}
+
+import "math/bits"
+
+// Generic versions of field arithmetic. Reductions select results with masks
+// rather than branches.
+
+func {{ .Name "add" }}(z, x, y *{{ .Type }}) {
+	var c, b uint64
+	var t, s [4]uint64
+	t[0], c = bits.Add64(x[0], y[0], 0)
+	t[1], c = bits.Add64(x[1], y[1], c)
+	t[2], c = bits.Add64(x[2], y[2], c)
+	t[3], c = bits.Add64(x[3], y[3], c)
+	s[0], b = bits.Sub64(t[0], {{ .P 0 }}, 0)
+	s[1], b = bits.Sub64(t[1], {{ .P 1 }}, b)
+	s[2], b = bits.Sub64(t[2], {{ .P 2 }}, b)
+	s[3], b = bits.Sub64(t[3], {{ .P 3 }}, b)
+	_, b = bits.Sub64(c, 0, b)
+	// keep t - p if it does not borrow
+	mask := b - 1
+	z[0] = (s[0] & mask) | (t[0] &^ mask)
+	z[1] = (s[1] & mask) | (t[1] &^ mask)
+	z[2] = (s[2] & mask) | (t[2] &^ mask)
+	z[3] = (s[3] & mask) | (t[3] &^ mask)
+}
+
+func {{ .Name "addAssign" }}(z, y *{{ .Type }}) {
+	{{ .Name "add" }}(z, z, y)
+}
+
+func {{ .Name "laddAssign" }}(z, y *{{ .Type }}) {
+	var c uint64
+	z[0], c = bits.Add64(z[0], y[0], 0)
+	z[1], c = bits.Add64(z[1], y[1], c)
+	z[2], c = bits.Add64(z[2], y[2], c)
+	z[3], _ = bits.Add64(z[3], y[3], c)
+}
+
+func {{ .Name "double" }}(z, x *{{ .Type }}) {
+	{{ .Name "add" }}(z, x, x)
+}
+
+func {{ .Name "doubleAssign" }}(z *{{ .Type }}) {
+	{{ .Name "add" }}(z, z, z)
+}
+
+func {{ .Name "sub" }}(z, x, y *{{ .Type }}) {
+	var b, c uint64
+	var t [4]uint64
+	t[0], b = bits.Sub64(x[0], y[0], 0)
+	t[1], b = bits.Sub64(x[1], y[1], b)
+	t[2], b = bits.Sub64(x[2], y[2], b)
+	t[3], b = bits.Sub64(x[3], y[3], b)
+	// add p back if subtraction borrows
+	mask := -b
+	z[0], c = bits.Add64(t[0], {{ .P 0 }}&mask, 0)
+	z[1], c = bits.Add64(t[1], {{ .P 1 }}&mask, c)
+	z[2], c = bits.Add64(t[2], {{ .P 2 }}&mask, c)
+	z[3], _ = bits.Add64(t[3], {{ .P 3 }}&mask, c)
+}
+
+func {{ .Name "subAssign" }}(z, y *{{ .Type }}) {
+	{{ .Name "sub" }}(z, z, y)
+}
+
+func {{ .Name "lsubAssign" }}(z, y *{{ .Type }}) uint64 {
+	var b uint64
+	z[0], b = bits.Sub64(z[0], y[0], 0)
+	z[1], b = bits.Sub64(z[1], y[1], b)
+	z[2], b = bits.Sub64(z[2], y[2], b)
+	z[3], b = bits.Sub64(z[3], y[3], b)
+	return b
+}
+
+func {{ .Name "neg" }}(z, x *{{ .Type }}) {
+	var b uint64
+	var t [4]uint64
+	t[0], b = bits.Sub64({{ .P 0 }}, x[0], 0)
+	t[1], b = bits.Sub64({{ .P 1 }}, x[1], b)
+	t[2], b = bits.Sub64({{ .P 2 }}, x[2], b)
+	t[3], _ = bits.Sub64({{ .P 3 }}, x[3], b)
+	// zero stays zero
+	nz := x[0] | x[1] | x[2] | x[3]
+	mask := -((nz | -nz) >> 63)
+	z[0] = t[0] & mask
+	z[1] = t[1] & mask
+	z[2] = t[2] & mask
+	z[3] = t[3] & mask
+}
+
+{{ template "mul" . }}
+
+func {{ .Name "square" }}(z, x *{{ .Type }}) {
+	{{ .Name "mul" }}(z, x, x)
+}
+`))
+
+// mulFallback returns Montgomery multiplication in coarsely integrated operand scanning
+// form, fully unrolled with modulus limbs as constants. Since the top limb of the modulus
+// leaves a free bit the final carry of each round fits into the top word.
+func (f *field) mulFallback() string {
+	var b bytes.Buffer
+	w := func(format string, a ...interface{}) {
+		fmt.Fprintf(&b, format+"\n", a...)
+	}
+	madd := func(i int) string {
+		return f.name(fmt.Sprintf("madd%d", i))
+	}
+	w("func %s(z, x, y *%s) {", f.name("mul"), f.typ)
+	w("var t [4]uint64")
+	w("var c [3]uint64")
+	for i := 0; i < 4; i++ {
+		w("{")
+		w("// round %d", i)
+		w("v := x[%d]", i)
+		if i == 0 {
+			w("c[1], c[0] = bits.Mul64(v, y[0])")
+		} else {
+			w("c[1], c[0] = %s(v, y[0], t[0])", madd(1))
+		}
+		w("m := c[0] * %s", f.Inp())
+		w("c[2] = %s(m, %s, c[0])", madd(0), f.P(0))
+		for j := 1; j < 4; j++ {
+			if i == 0 {
+				w("c[1], c[0] = %s(v, y[%d], c[1])", madd(1), j)
+			} else {
+				w("c[1], c[0] = %s(v, y[%d], c[1], t[%d])", madd(2), j, j)
+			}
+			out := fmt.Sprintf("t[%d]", j-1)
+			if i == 3 {
+				out = fmt.Sprintf("z[%d]", j-1)
+			}
+			if j < 3 {
+				w("c[2], %s = %s(m, %s, c[2], c[0])", out, madd(2), f.P(j))
+			} else if i < 3 {
+				w("t[3], t[2] = %s(m, %s, c[0], c[2], c[1])", madd(3), f.P(j))
+			} else {
+				w("z[3], z[2] = %s(m, %s, c[0], c[2], c[1])", madd(3), f.P(j))
+			}
+		}
+		w("}")
+	}
+	w("// keep z - p if it does not borrow")
+	w("var s [4]uint64")
+	w("var k uint64")
+	w("s[0], k = bits.Sub64(z[0], %s, 0)", f.P(0))
+	for j := 1; j < 4; j++ {
+		w("s[%d], k = bits.Sub64(z[%d], %s, k)", j, j, f.P(j))
+	}
+	w("mask := k - 1")
+	for j := 0; j < 4; j++ {
+		w("z[%d] = (s[%d] & mask) | (z[%d] &^ mask)", j, j, j)
+	}
+	w("}")
+	w("")
+	w("// %s hi = a*b + c (discards lo bits)", madd(0))
+	w("func %s(a, b, c uint64) (hi uint64) {", madd(0))
+	w("var carry, lo uint64")
+	w("hi, lo = bits.Mul64(a, b)")
+	w("_, carry = bits.Add64(lo, c, 0)")
+	w("hi, _ = bits.Add64(hi, 0, carry)")
+	w("return")
+	w("}")
+	w("")
+	w("// %s hi, lo = a*b + c", madd(1))
+	w("func %s(a, b, c uint64) (hi uint64, lo uint64) {", madd(1))
+	w("var carry uint64")
+	w("hi, lo = bits.Mul64(a, b)")
+	w("lo, carry = bits.Add64(lo, c, 0)")
+	w("hi, _ = bits.Add64(hi, 0, carry)")
+	w("return")
+	w("}")
+	w("")
+	w("// %s hi, lo = a*b + c + d", madd(2))
+	w("func %s(a, b, c, d uint64) (hi uint64, lo uint64) {", madd(2))
+	w("var carry uint64")
+	w("hi, lo = bits.Mul64(a, b)")
+	w("c, carry = bits.Add64(c, d, 0)")
+	w("hi, _ = bits.Add64(hi, 0, carry)")
+	w("lo, carry = bits.Add64(lo, c, 0)")
+	w("hi, _ = bits.Add64(hi, 0, carry)")
+	w("return")
+	w("}")
+	w("")
+	w("// %s hi, lo = a*b + c + d + e*2^64", madd(3))
+	w("func %s(a, b, c, d, e uint64) (hi uint64, lo uint64) {", madd(3))
+	w("var carry uint64")
+	w("hi, lo = bits.Mul64(a, b)")
+	w("c, carry = bits.Add64(c, d, 0)")
+	w("hi, _ = bits.Add64(hi, 0, carry)")
+	w("lo, carry = bits.Add64(lo, c, 0)")
+	w("hi, _ = bits.Add64(hi, e, carry)")
+	w("return")
+	w("}")
+	return b.String()
+}
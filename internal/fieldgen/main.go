@@ -0,0 +1,171 @@
+// Command fieldgen generates Montgomery arithmetic for prime fields with 4 limb moduli.
+//
+// For a given modulus it emits amd64 assembly with ADX/BMI2 and generic multiplication and
+// squaring, the Go declarations with CPU feature dispatch and a pure Go fallback for
+// other architectures and the generic build tag. Assembly reads the modulus and the
+// Montgomery constant from package variables that are expected to be declared next to
+// the field type, their names are derived from the prefix. If a wide type is given,
+// double width multiplication, Montgomery reduction, addition and subtraction for lazy
+// reduction are generated as well.
+//
+// Usage:
+//
+//	go run ./internal/fieldgen -modulus 0x30644e...fd47 -type fe -output arithmetic -wide wfe
+//	go run ./internal/fieldgen -modulus 0x30644e...0001 -type Fr -prefix fr -output fr_arithmetic
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"math/big"
+	"path/filepath"
+	"strings"
+)
+
+type config struct {
+	modulus string
+	typ     string
+	prefix  string
+	pkg     string
+	output  string
+	wide    string
+}
+
+func (c *config) register(fs *flag.FlagSet) {
+	fs.StringVar(&c.modulus, "modulus", "", "field modulus in hex")
+	fs.StringVar(&c.typ, "type", "fe", "go type of field elements")
+	fs.StringVar(&c.prefix, "prefix", "", "prefix of generated functions and modulus variables")
+	fs.StringVar(&c.pkg, "package", "bn254", "package name of generated files")
+	fs.StringVar(&c.output, "output", "arithmetic", "file name stem of generated files")
+	fs.StringVar(&c.wide, "wide", "", "go type of double width elements, wide arithmetic is generated if set")
+}
+
+type field struct {
+	config
+	p     *big.Int
+	limbs [4]uint64
+	inp   uint64
+}
+
+func newField(c config) (*field, error) {
+	p, ok := new(big.Int).SetString(strings.TrimPrefix(c.modulus, "0x"), 16)
+	if !ok {
+		return nil, errors.New("modulus should be given in hex")
+	}
+	if p.Bit(0) == 0 {
+		return nil, errors.New("modulus should be odd")
+	}
+	if p.BitLen() <= 192 || p.BitLen() > 256 {
+		return nil, errors.New("modulus should be between 193 and 256 bits")
+	}
+	f := &field{config: c, p: p}
+	t := new(big.Int).Set(p)
+	mask := new(big.Int).SetUint64(^uint64(0))
+	for i := 0; i < 4; i++ {
+		f.limbs[i] = new(big.Int).And(t, mask).Uint64()
+		t.Rsh(t, 64)
+	}
+	// top bit of the modulus is kept free so that intermediate carries fit into
+	// the reduction and lazy additions of two elements do not overflow
+	if f.limbs[3] >= 1<<63-1 {
+		return nil, errors.New("top limb of modulus should be less than 2^63 - 1")
+	}
+	// inp = -p^(-1) mod 2^64
+	r := new(big.Int).Lsh(big.NewInt(1), 64)
+	inp := new(big.Int).ModInverse(new(big.Int).SetUint64(f.limbs[0]), r)
+	f.inp = inp.Sub(r, inp).Uint64()
+	return f, nil
+}
+
+// name returns the generated name of an operation, operations of a prefixed field are
+// camel cased, eg. mulADX becomes frMulADX.
+func (f *field) name(op string) string {
+	if f.prefix == "" {
+		return op
+	}
+	return f.prefix + strings.ToUpper(op[:1]) + op[1:]
+}
+
+func (f *field) modulusVar() string {
+	if f.prefix == "" {
+		return "modulus"
+	}
+	return f.prefix + "Modulus"
+}
+
+func (f *field) inpVar() string {
+	if f.prefix == "" {
+		return "inp"
+	}
+	return f.prefix + "Inp"
+}
+
+func (f *field) command() string {
+	args := []string{"-modulus", f.modulus, "-type", f.typ}
+	if f.prefix != "" {
+		args = append(args, "-prefix", f.prefix)
+	}
+	if f.pkg != "bn254" {
+		args = append(args, "-package", f.pkg)
+	}
+	args = append(args, "-output", f.output)
+	if f.wide != "" {
+		args = append(args, "-wide", f.wide)
+	}
+	return "fieldgen " + strings.Join(args, " ")
+}
+
+// generate returns generated sources keyed by file name.
+func (f *field) generate() (map[string][]byte, error) {
+	decl, err := f.decl()
+	if err != nil {
+		return nil, err
+	}
+	fallback, err := f.fallback()
+	if err != nil {
+		return nil, err
+	}
+	files := map[string][]byte{
+		f.output + ".s":           f.asm(),
+		f.output + "_decl.go":     decl,
+		f.output + "_fallback.go": fallback,
+	}
+	if f.wide == "" {
+		return files, nil
+	}
+	wideDecl, err := f.execute(wideDeclTemplate)
+	if err != nil {
+		return nil, err
+	}
+	wideFallback, err := f.execute(wideFallbackTemplate)
+	if err != nil {
+		return nil, err
+	}
+	files[f.output+"_wide.s"] = f.wideAsm()
+	files[f.output+"_wide_decl.go"] = wideDecl
+	files[f.output+"_wide_fallback.go"] = wideFallback
+	return files, nil
+}
+
+func main() {
+	var c config
+	c.register(flag.CommandLine)
+	dir := flag.String("dir", ".", "output directory")
+	flag.Parse()
+	f, err := newField(c)
+	if err != nil {
+		log.Fatal(err)
+	}
+	files, err := f.generate()
+	if err != nil {
+		log.Fatal(err)
+	}
+	for name, src := range files {
+		if err := ioutil.WriteFile(filepath.Join(*dir, name), src, 0644); err != nil {
+			log.Fatal(fmt.Errorf("writing %s: %v", name, err))
+		}
+	}
+}
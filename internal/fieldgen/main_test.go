@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestGeneratedFilesUpToDate checks that sources generated by go:generate directives of the
+// root package are in sync with the generator.
+func TestGeneratedFilesUpToDate(t *testing.T) {
+	root := filepath.Join("..", "..")
+	names, err := filepath.Glob(filepath.Join(root, "*.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	directive := "//go:generate go run ./internal/fieldgen "
+	found := 0
+	for _, name := range names {
+		file, err := os.Open(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			line := scanner.Text()
+			if !strings.HasPrefix(line, directive) {
+				continue
+			}
+			found++
+			var c config
+			fs := flag.NewFlagSet("fieldgen", flag.ContinueOnError)
+			c.register(fs)
+			if err := fs.Parse(strings.Fields(strings.TrimPrefix(line, directive))); err != nil {
+				t.Fatal(err)
+			}
+			f, err := newField(c)
+			if err != nil {
+				t.Fatal(err)
+			}
+			files, err := f.generate()
+			if err != nil {
+				t.Fatal(err)
+			}
+			for out, src := range files {
+				existing, err := ioutil.ReadFile(filepath.Join(root, out))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !bytes.Equal(existing, src) {
+					t.Fatalf("%s is not up to date, run go generate", out)
+				}
+			}
+		}
+		file.Close()
+	}
+	if found == 0 {
+		t.Fatal("no generate directive found")
+	}
+}
+
+func TestInvalidModulus(t *testing.T) {
+	for _, m := range []string{
+		"0x30644e72e131a029b85045b68181585d97816a916871ca8d3c208c16d87cfd46",
+		"0xb0644e72e131a029b85045b68181585d97816a916871ca8d3c208c16d87cfd47",
+		"0x97816a916871ca8d3c208c16d87cfd47",
+		"0xzz",
+	} {
+		if _, err := newField(config{modulus: m, typ: "fe"}); err == nil {
+			t.Fatalf("modulus %s should be rejected", m)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package main
+
+import "text/template"
+
+func (f *field) Wide() string {
+	return f.wide
+}
+
+var wideDeclTemplate = template.Must(template.New("wideDecl").Parse(`//go:build amd64 && !generic
+// +build amd64,!generic
+
+// Code generated by {{ .Command }}. DO NOT EDIT.
+
+package {{ .Package }}
+
+//go:noescape
+func {{ .Name "wmul" }}(c *{{ .Wide }}, a, b *{{ .Type }})
+
+//go:noescape
+func {{ .Name "montRed" }}(c *{{ .Type }}, a *{{ .Wide }})
+
+//go:noescape
+func {{ .Name "wadd" }}(c, a, b *{{ .Wide }})
+
+//go:noescape
+func {{ .Name "wsub" }}(c, a, b *{{ .Wide }})
+`))
+
+var wideFallbackTemplate = template.Must(template.New("wideFallback").Parse(`//go:build !amd64 || generic
+// +build !amd64 generic
+
+// Code generated by {{ .Command }}. DO NOT EDIT.
+
+package {{ .Package }}
+
+import "math/bits"
+
+// Double width arithmetic for lazy reduction. Wide elements are 512 bit integers in [0, p * 2^256).
+
+// {{ .Name "wmul" }} computes 512 bit product of two field elements without reduction.
+func {{ .Name "wmul" }}(z *{{ .Wide }}, x, y *{{ .Type }}) {
+	var t {{ .Wide }}
+	for i := 0; i < 4; i++ {
+		var carry uint64
+		for j := 0; j < 4; j++ {
+			hi, lo := bits.Mul64(x[i], y[j])
+			var c uint64
+			lo, c = bits.Add64(lo, t[i+j], 0)
+			hi += c
+			lo, c = bits.Add64(lo, carry, 0)
+			hi += c
+			t[i+j], carry = lo, hi
+		}
+		t[i+4] = carry
+	}
+	*z = t
+}
+
+// {{ .Name "montRed" }} applies Montgomery reduction to a wide element.
+func {{ .Name "montRed" }}(z *{{ .Type }}, x *{{ .Wide }}) {
+	p := [4]uint64{ {{- .P 0 }}, {{ .P 1 }}, {{ .P 2 }}, {{ .P 3 -}} }
+	t := *x
+	var top uint64
+	for i := 0; i < 4; i++ {
+		m := t[i] * {{ .Inp }}
+		var carry uint64
+		for j := 0; j < 4; j++ {
+			hi, lo := bits.Mul64(m, p[j])
+			var c uint64
+			lo, c = bits.Add64(lo, t[i+j], 0)
+			hi += c
+			lo, c = bits.Add64(lo, carry, 0)
+			hi += c
+			t[i+j], carry = lo, hi
+		}
+		var c uint64
+		t[i+4], c = bits.Add64(t[i+4], carry, 0)
+		for k := i + 5; k < 8; k++ {
+			t[k], c = bits.Add64(t[k], 0, c)
+		}
+		top += c
+	}
+	var b uint64
+	var s [4]uint64
+	s[0], b = bits.Sub64(t[4], p[0], 0)
+	s[1], b = bits.Sub64(t[5], p[1], b)
+	s[2], b = bits.Sub64(t[6], p[2], b)
+	s[3], b = bits.Sub64(t[7], p[3], b)
+	_, b = bits.Sub64(top, 0, b)
+	mask := -b
+	z[0] = t[4]&mask | s[0]&^mask
+	z[1] = t[5]&mask | s[1]&^mask
+	z[2] = t[6]&mask | s[2]&^mask
+	z[3] = t[7]&mask | s[3]&^mask
+}
+
+// {{ .Name "wadd" }} adds two wide elements modulo p * 2^256.
+func {{ .Name "wadd" }}(z, x, y *{{ .Wide }}) {
+	var c, b uint64
+	var t {{ .Wide }}
+	for i := 0; i < 8; i++ {
+		t[i], c = bits.Add64(x[i], y[i], c)
+	}
+	var s [4]uint64
+	s[0], b = bits.Sub64(t[4], {{ .P 0 }}, 0)
+	s[1], b = bits.Sub64(t[5], {{ .P 1 }}, b)
+	s[2], b = bits.Sub64(t[6], {{ .P 2 }}, b)
+	s[3], b = bits.Sub64(t[7], {{ .P 3 }}, b)
+	_, b = bits.Sub64(c, 0, b)
+	mask := -b
+	t[4] = t[4]&mask | s[0]&^mask
+	t[5] = t[5]&mask | s[1]&^mask
+	t[6] = t[6]&mask | s[2]&^mask
+	t[7] = t[7]&mask | s[3]&^mask
+	*z = t
+}
+
+// {{ .Name "wsub" }} subtracts two wide elements modulo p * 2^256.
+func {{ .Name "wsub" }}(z, x, y *{{ .Wide }}) {
+	var b, c uint64
+	var t {{ .Wide }}
+	for i := 0; i < 8; i++ {
+		t[i], b = bits.Sub64(x[i], y[i], b)
+	}
+	mask := -b
+	t[4], c = bits.Add64(t[4], {{ .P 0 }}&mask, 0)
+	t[5], c = bits.Add64(t[5], {{ .P 1 }}&mask, c)
+	t[6], c = bits.Add64(t[6], {{ .P 2 }}&mask, c)
+	t[7], _ = bits.Add64(t[7], {{ .P 3 }}&mask, c)
+	*z = t
+}
+`))
@@ -0,0 +1,171 @@
+// Package kzg implements the Kate-Zaverucha-Goldberg polynomial commitment scheme over BN254.
+package kzg
+
+import (
+	"errors"
+	"io"
+	"math/big"
+
+	"github.com/kilic/bn254"
+	"github.com/kilic/bn254/poly"
+)
+
+type Fr = bn254.Fr
+
+// ProvingKey holds powers of the secret in G1, [G, tau * G, ..., tau^(n-1) * G].
+type ProvingKey struct {
+	G1 []*bn254.PointG1
+}
+
+// VerifyingKey holds the generators and the secret in G2.
+type VerifyingKey struct {
+	G1 *bn254.PointG1
+	G2 [2]*bn254.PointG2
+}
+
+// SRS is the structured reference string that commits to polynomials with at most
+// len(Pk.G1) coefficients.
+type SRS struct {
+	Pk ProvingKey
+	Vk VerifyingKey
+}
+
+// OpeningProof claims that committed polynomial evaluates to ClaimedValue at Point.
+type OpeningProof struct {
+	H            *bn254.PointG1
+	Point        Fr
+	ClaimedValue Fr
+}
+
+// NewSRSInsecure generates a reference string of given size from a known secret. Anyone
+// who knows tau can forge proofs, it should be used only in tests.
+func NewSRSInsecure(size int, tau *Fr) (*SRS, error) {
+	if size < 1 {
+		return nil, errors.New("size of reference string should be positive")
+	}
+	g1, g2 := bn254.NewG1(), bn254.NewG2()
+	srs := &SRS{}
+	srs.Pk.G1 = make([]*bn254.PointG1, size)
+	w := new(Fr).One()
+	for i := range srs.Pk.G1 {
+		srs.Pk.G1[i] = g1.MulScalar(g1.New(), g1.One(), w.Big())
+		w.Mul(w, tau)
+	}
+	srs.Vk.G1 = g1.One()
+	srs.Vk.G2[0] = g2.One()
+	srs.Vk.G2[1] = g2.MulScalar(g2.New(), g2.One(), tau.Big())
+	return srs, nil
+}
+
+// Commit returns the commitment to polynomial p which is p(tau) * G.
+func Commit(pk *ProvingKey, p poly.Polynomial) (*bn254.PointG1, error) {
+	if len(p) > len(pk.G1) {
+		return nil, errors.New("polynomial is larger than reference string")
+	}
+	g := bn254.NewG1()
+	return g.MultiExp(g.New(), pk.G1[:len(p)], p.Big())
+}
+
+// Open evaluates p at z and returns the proof which is the commitment to
+// (p(X) - p(z)) / (X - z).
+func Open(pk *ProvingKey, p poly.Polynomial, z *Fr) (*OpeningProof, error) {
+	q, v := poly.DivideByLinear(p, z)
+	h, err := Commit(pk, q)
+	if err != nil {
+		return nil, err
+	}
+	proof := &OpeningProof{H: h}
+	proof.Point.Set(z)
+	proof.ClaimedValue.Set(v)
+	return proof, nil
+}
+
+// Verify checks the opening proof of the commitment with a single pairing check
+// e(C - v * G + z * H, G2) * e(-H, tau * G2) == 1.
+func Verify(vk *VerifyingKey, commitment *bn254.PointG1, proof *OpeningProof) (bool, error) {
+	g := bn254.NewG1()
+	if !g.IsOnCurve(commitment) || !g.IsOnCurve(proof.H) {
+		return false, errors.New("point is not on curve")
+	}
+	t, u := g.New(), g.New()
+	g.MulScalar(t, vk.G1, proof.ClaimedValue.Big())
+	g.Sub(t, commitment, t)
+	g.MulScalar(u, proof.H, proof.Point.Big())
+	g.Add(t, t, u)
+	e := bn254.NewEngine()
+	e.AddPair(t, vk.G2[0])
+	e.AddPairInv(proof.H, vk.G2[1])
+	return e.Check(), nil
+}
+
+// BatchOpen opens each polynomial at the corresponding point.
+func BatchOpen(pk *ProvingKey, polynomials []poly.Polynomial, points []Fr) ([]*OpeningProof, error) {
+	if len(polynomials) != len(points) {
+		return nil, errors.New("polynomial and point vectors should be in same length")
+	}
+	proofs := make([]*OpeningProof, len(polynomials))
+	for i := range polynomials {
+		proof, err := Open(pk, polynomials[i], &points[i])
+		if err != nil {
+			return nil, err
+		}
+		proofs[i] = proof
+	}
+	return proofs, nil
+}
+
+// BatchVerify checks opening proofs of commitments at possibly different points with a
+// single pairing check. Individual equations are combined with random coefficients r_i
+// drawn from given source,
+// e(sum r_i * (C_i - v_i * G + z_i * H_i), G2) * e(-sum r_i * H_i, tau * G2) == 1.
+func BatchVerify(vk *VerifyingKey, commitments []*bn254.PointG1, proofs []*OpeningProof, r io.Reader) (bool, error) {
+	n := len(commitments)
+	if n != len(proofs) {
+		return false, errors.New("commitment and proof vectors should be in same length")
+	}
+	if n == 0 {
+		return true, nil
+	}
+	g := bn254.NewG1()
+	// left hand side bases are commitments, proofs and the generator
+	lBases := make([]*bn254.PointG1, 0, 2*n+1)
+	lScalars := make([]*Fr, 0, 2*n+1)
+	rBases := make([]*bn254.PointG1, n)
+	rScalars := make([]*Fr, n)
+	v := new(Fr)
+	for i := 0; i < n; i++ {
+		if !g.IsOnCurve(commitments[i]) || !g.IsOnCurve(proofs[i].H) {
+			return false, errors.New("point is not on curve")
+		}
+		ri, err := new(Fr).Rand(r)
+		if err != nil {
+			return false, err
+		}
+		lBases = append(lBases, commitments[i], proofs[i].H)
+		lScalars = append(lScalars, ri, new(Fr).Mul(ri, &proofs[i].Point))
+		v.Add(v, new(Fr).Mul(ri, &proofs[i].ClaimedValue))
+		rBases[i], rScalars[i] = proofs[i].H, ri
+	}
+	lBases = append(lBases, vk.G1)
+	lScalars = append(lScalars, v.Neg(v))
+	left, err := g.MultiExp(g.New(), lBases, bigs(lScalars))
+	if err != nil {
+		return false, err
+	}
+	right, err := g.MultiExp(g.New(), rBases, bigs(rScalars))
+	if err != nil {
+		return false, err
+	}
+	e := bn254.NewEngine()
+	e.AddPair(left, vk.G2[0])
+	e.AddPairInv(right, vk.G2[1])
+	return e.Check(), nil
+}
+
+func bigs(a []*Fr) []*big.Int {
+	r := make([]*big.Int, len(a))
+	for i := range a {
+		r[i] = a[i].Big()
+	}
+	return r
+}
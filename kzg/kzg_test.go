@@ -0,0 +1,137 @@
+package kzg
+
+import (
+	"crypto/rand"
+	"testing"
+
+	"github.com/kilic/bn254"
+	"github.com/kilic/bn254/poly"
+)
+
+func randFr() *Fr {
+	a, err := new(Fr).Rand(rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
+
+func randPoly(n int) poly.Polynomial {
+	p := make(poly.Polynomial, n)
+	for i := range p {
+		p[i].Set(randFr())
+	}
+	return p
+}
+
+func newTestSRS(t *testing.T, size int) *SRS {
+	srs, err := NewSRSInsecure(size, randFr())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return srs
+}
+
+func TestCommitment(t *testing.T) {
+	if _, err := NewSRSInsecure(0, randFr()); err == nil {
+		t.Fatal("empty reference string should be rejected")
+	}
+	tau := randFr()
+	srs, _ := NewSRSInsecure(16, tau)
+	p := randPoly(16)
+	c, err := Commit(&srs.Pk, p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := bn254.NewG1()
+	if !g.Equal(c, g.MulScalar(g.New(), g.One(), p.Evaluate(tau).Big())) {
+		t.Fatal("commitment is p(tau) * G")
+	}
+	if _, err := Commit(&srs.Pk, randPoly(17)); err == nil {
+		t.Fatal("polynomial larger than reference string should be rejected")
+	}
+}
+
+func TestOpening(t *testing.T) {
+	srs := newTestSRS(t, 32)
+	for _, n := range []int{1, 2, 32} {
+		p := randPoly(n)
+		c, err := Commit(&srs.Pk, p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		z := randFr()
+		proof, err := Open(&srs.Pk, p, z)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !proof.ClaimedValue.Equal(p.Evaluate(z)) {
+			t.Fatal("claimed value is p(z)")
+		}
+		ok, err := Verify(&srs.Vk, c, proof)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			t.Fatal("valid opening is rejected")
+		}
+		proof.ClaimedValue.Add(&proof.ClaimedValue, new(Fr).One())
+		if ok, _ := Verify(&srs.Vk, c, proof); ok {
+			t.Fatal("opening to a wrong value is accepted")
+		}
+		if n == 1 {
+			// constant polynomial takes the same value at any point
+			continue
+		}
+		proof.ClaimedValue.Set(p.Evaluate(z))
+		proof.Point.Set(randFr())
+		if ok, _ := Verify(&srs.Vk, c, proof); ok {
+			t.Fatal("opening at a wrong point is accepted")
+		}
+	}
+}
+
+func TestBatchOpening(t *testing.T) {
+	srs := newTestSRS(t, 16)
+	n := 5
+	polynomials := make([]poly.Polynomial, n)
+	commitments := make([]*bn254.PointG1, n)
+	points := make([]Fr, n)
+	for i := range polynomials {
+		polynomials[i] = randPoly(16 - i)
+		c, err := Commit(&srs.Pk, polynomials[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		commitments[i] = c
+		points[i].Set(randFr())
+	}
+	// two polynomials are opened at the same point
+	points[1].Set(&points[0])
+	proofs, err := BatchOpen(&srs.Pk, polynomials, points)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ok, err := BatchVerify(&srs.Vk, commitments, proofs, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatal("valid batch is rejected")
+	}
+	proofs[3].ClaimedValue.Add(&proofs[3].ClaimedValue, new(Fr).One())
+	if ok, _ := BatchVerify(&srs.Vk, commitments, proofs, rand.Reader); ok {
+		t.Fatal("batch with a wrong value is accepted")
+	}
+	proofs[3].ClaimedValue.Set(polynomials[3].Evaluate(&points[3]))
+	commitments[0], commitments[1] = commitments[1], commitments[0]
+	if ok, _ := BatchVerify(&srs.Vk, commitments, proofs, rand.Reader); ok {
+		t.Fatal("batch with swapped commitments is accepted")
+	}
+	if _, err := BatchVerify(&srs.Vk, commitments[1:], proofs, rand.Reader); err == nil {
+		t.Fatal("vectors should be in same length")
+	}
+	if _, err := BatchOpen(&srs.Pk, polynomials, points[1:]); err == nil {
+		t.Fatal("vectors should be in same length")
+	}
+}
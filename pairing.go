@@ -1,15 +1,30 @@
 package bn254
 
+import (
+	"errors"
+	"runtime"
+	"sync"
+)
+
 type pair struct {
-	g1 *PointG1
-	g2 *PointG2
+	g1       *PointG1
+	g2       *PointG2
+	prepared *PreparedG2
 }
 
 func newPair(g1 *PointG1, g2 *PointG2) pair {
-	return pair{g1, g2}
+	return pair{g1, g2, nil}
+}
+
+// PreparedG2 holds precomputed Miller loop line coefficients of a G2 point.
+// A prepared point is read only and can be shared across engines.
+type PreparedG2 struct {
+	coeffs   [102][3]fe2
+	infinity bool
 }
 
 // Engine is BN254 elliptic curve pairing engine
+// Engine holds scratch space and a pair stack, it is not safe for concurrent use, see SafeEngine.
 type Engine struct {
 	G1   *G1
 	G2   *G2
@@ -72,6 +87,40 @@ func (e *Engine) addPair(g1 *PointG1, g2 *PointG2) *Engine {
 	return e
 }
 
+// AddPairPrepared adds a g1 point and a prepared g2 point pair to pairing engine
+func (e *Engine) AddPairPrepared(g1 *PointG1, g2 *PreparedG2) *Engine {
+	return e.addPairPrepared(e.G1.New().Set(g1), g2)
+}
+
+// AddPairPreparedInv adds a g1 point and a prepared g2 point pair to pairing engine. G1 point is negated.
+func (e *Engine) AddPairPreparedInv(g1 *PointG1, g2 *PreparedG2) *Engine {
+	ng1 := e.G1.New().Set(g1)
+	e.G1.Neg(ng1, ng1)
+	return e.addPairPrepared(ng1, g2)
+}
+
+func (e *Engine) addPairPrepared(g1 *PointG1, g2 *PreparedG2) *Engine {
+	if !e.G1.IsZero(g1) && !g2.infinity {
+		e.G1.Affine(g1)
+		e.pairs = append(e.pairs, pair{g1, nil, g2})
+	}
+	return e
+}
+
+// PrepareG2 precomputes line coefficients of a G2 point so that
+// the point can be reused in many pairings without doubling and addition steps.
+func (e *Engine) PrepareG2(p *PointG2) *PreparedG2 {
+	prepared := &PreparedG2{}
+	if e.G2.IsZero(p) {
+		prepared.infinity = true
+		return prepared
+	}
+	q := e.G2.New().Set(p)
+	e.G2.Affine(q)
+	e.prepare(&prepared.coeffs, q)
+	return prepared
+}
+
 // Reset removes pair stack.
 func (e *Engine) Reset() *Engine {
 	e.pairs = []pair{}
@@ -182,11 +231,15 @@ func (e *Engine) prepare(ellCoeffs *[102][3]fe2, twistPoint *PointG2) {
 	e.additionStep(&ellCoeffs[j], r, Q2)
 }
 
-func (e *Engine) millerLoop(f *fe12) {
-	pairs := e.pairs
-	ellCoeffs := make([][102][3]fe2, len(pairs))
+func (e *Engine) millerLoop(f *fe12, pairs []pair) {
+	ellCoeffs := make([]*[102][3]fe2, len(pairs))
 	for i := 0; i < len(pairs); i++ {
-		e.prepare(&ellCoeffs[i], pairs[i].g2)
+		if pairs[i].prepared != nil {
+			ellCoeffs[i] = &pairs[i].prepared.coeffs
+			continue
+		}
+		ellCoeffs[i] = new([102][3]fe2)
+		e.prepare(ellCoeffs[i], pairs[i].g2)
 	}
 
 	fp12, fp2 := e.fp12, e.fp2
@@ -227,47 +280,15 @@ func (e *Engine) millerLoop(f *fe12) {
 	}
 }
 
+// exp raises a to u. Karabina's compressed squarings do not pay off for u, decompression of
+// squares at its 24 non zero NAF digits costs more than the squarings save.
 func (e *Engine) exp(c, a *fe12) {
-	fp12 := e.fp12
-	fp12.cyclotomicExp(c, a, u)
-}
-
-func (e *Engine) finalExpX(f *fe12) {
-	fp12 := e.fp12
-	t := e.t12
-	// easy part
-	fp12.frobeniusMap(&t[0], f, 6)
-	fp12.inverse(&t[1], f)
-	fp12.mul(&t[2], &t[0], &t[1])
-	t[1].set(&t[2])
-	fp12.frobeniusMapAssign(&t[2], 2)
-	fp12.mulAssign(&t[2], &t[1])
-	fp12.cyclotomicSquare(&t[1], &t[2])
-	fp12.conjugate(&t[1], &t[1])
-	// hard part
-	e.exp(&t[3], &t[2])
-	fp12.cyclotomicSquare(&t[4], &t[3])
-	fp12.mul(&t[5], &t[1], &t[3])
-	e.exp(&t[1], &t[5])
-	e.exp(&t[0], &t[1])
-	e.exp(&t[6], &t[0])
-	fp12.mulAssign(&t[6], &t[4])
-	e.exp(&t[4], &t[6])
-	fp12.conjugate(&t[5], &t[5])
-	fp12.mulAssign(&t[4], &t[5])
-	fp12.mulAssign(&t[4], &t[2])
-	fp12.conjugate(&t[5], &t[2])
-	fp12.mulAssign(&t[1], &t[2])
-	fp12.frobeniusMapAssign(&t[1], 3)
-	fp12.mulAssign(&t[6], &t[5])
-	fp12.frobeniusMapAssign(&t[6], 1)
-	fp12.mulAssign(&t[3], &t[0])
-	fp12.frobeniusMapAssign(&t[3], 2)
-	fp12.mulAssign(&t[3], &t[1])
-	fp12.mulAssign(&t[3], &t[6])
-	fp12.mul(f, &t[3], &t[4])
+	e.fp12.cyclotomicExp(c, a, u)
 }
 
+// finalExp computes f^((p^12 - 1) / q), hard part follows Scott et al. https://eprint.iacr.org/2008/490.pdf
+// It computes the pairing value itself and is used where the value is exposed such as Result and
+// FinalExponentiation so that results are compatible with other implementations.
 func (e *Engine) finalExp(f *fe12) {
 	fp12 := e.fp12
 	t := e.t12
@@ -311,24 +332,172 @@ func (e *Engine) finalExp(f *fe12) {
 	f.set(&t[1])
 }
 
-func (e *Engine) calculate() *fe12 {
+// finalExpPowered computes f^(m * (p^12 - 1) / q) where m = 2u(6u^2 + 3u + 1).
+// Hard part is the addition chain of Duquesne and Ghammam https://eprint.iacr.org/2015/192.pdf
+// which follows Fuentes-Castañeda et al. https://www.cs.ucdavis.edu/~rogaway/papers/latin.pdf
+// m is coprime to q so the result is still a non degenerate bilinear pairing and it is equal to
+// one iff the pairing value is equal to one, which is all Check needs. The main benefit is the
+// shorter addition chain, it is only about 1% faster than finalExp.
+func (e *Engine) finalExpPowered(f *fe12) {
+	fp12 := e.fp12
+	t := e.t12
+	// easy part
+	fp12.frobeniusMap(&t[0], f, 6)
+	fp12.inverse(&t[1], f)
+	fp12.mulAssign(&t[1], &t[0])
+	fp12.frobeniusMap(&t[0], &t[1], 2)
+	fp12.mulAssign(&t[1], &t[0])
+	// hard part
+	// t2 = f^(-2u)
+	e.exp(&t[2], &t[1])
+	fp12.conjugate(&t[2], &t[2])
+	fp12.cyclotomicSquare(&t[2], &t[2])
+	// t3 = f^(-6u)
+	fp12.cyclotomicSquare(&t[3], &t[2])
+	fp12.mulAssign(&t[3], &t[2])
+	// t4 = f^(6u^2)
+	e.exp(&t[4], &t[3])
+	fp12.conjugate(&t[4], &t[4])
+	// t3 = f^(6u^2 + 6u)
+	fp12.conjugate(&t[5], &t[3])
+	fp12.mul(&t[3], &t[4], &t[5])
+	// t6 = f^(12u^3 + 6u^2 + 6u)
+	fp12.cyclotomicSquare(&t[5], &t[4])
+	e.exp(&t[6], &t[5])
+	fp12.mulAssign(&t[6], &t[3])
+	// t3 = f^(12u^3 + 6u^2 + 4u)
+	fp12.mul(&t[3], &t[2], &t[6])
+	// t2 = f^(12u^3 + 12u^2 + 6u + 1)
+	fp12.mul(&t[2], &t[4], &t[6])
+	fp12.mulAssign(&t[2], &t[1])
+	// multiply by frobenius images
+	fp12.frobeniusMap(&t[0], &t[3], 1)
+	fp12.mulAssign(&t[2], &t[0])
+	fp12.frobeniusMap(&t[0], &t[6], 2)
+	fp12.mulAssign(&t[2], &t[0])
+	fp12.conjugate(&t[0], &t[1])
+	fp12.mulAssign(&t[0], &t[3])
+	fp12.frobeniusMapAssign(&t[0], 3)
+	fp12.mul(f, &t[2], &t[0])
+}
+
+// calculate runs Miller loop over added pairs and applies given final exponentiation.
+func (e *Engine) calculate(finalExp func(f *fe12)) *fe12 {
 	f := e.fp12.one()
 	if len(e.pairs) == 0 {
 		return f
 	}
-	e.millerLoop(f)
-	e.finalExp(f)
+	e.millerLoop(f, e.pairs)
+	finalExp(f)
 	return f
 }
 
+// MillerLoop computes Miller loop of given pairs without final exponentiation and returns the
+// product as result. Pair stack of the engine is neither used nor modified. Results of separate
+// Miller loops can be multiplied and exponentiated once with FinalExponentiation.
+func (e *Engine) MillerLoop(g1s []*PointG1, g2s []*PointG2) (*E, error) {
+	if len(g1s) != len(g2s) {
+		return nil, errors.New("g1 and g2 point vectors should be in same length")
+	}
+	pairs := make([]pair, 0, len(g1s))
+	for i := range g1s {
+		p := newPair(e.G1.New().Set(g1s[i]), e.G2.New().Set(g2s[i]))
+		if !e.isZero(p) {
+			e.affine(p)
+			pairs = append(pairs, p)
+		}
+	}
+	f := e.fp12.one()
+	if len(pairs) != 0 {
+		e.millerLoop(f, pairs)
+	}
+	return f, nil
+}
+
+// MillerLoopPrepared is MillerLoop with prepared G2 points.
+func (e *Engine) MillerLoopPrepared(g1s []*PointG1, g2s []*PreparedG2) (*E, error) {
+	if len(g1s) != len(g2s) {
+		return nil, errors.New("g1 and g2 point vectors should be in same length")
+	}
+	pairs := make([]pair, 0, len(g1s))
+	for i := range g1s {
+		if e.G1.IsZero(g1s[i]) || g2s[i].infinity {
+			continue
+		}
+		g1 := e.G1.New().Set(g1s[i])
+		e.G1.Affine(g1)
+		pairs = append(pairs, pair{g1, nil, g2s[i]})
+	}
+	f := e.fp12.one()
+	if len(pairs) != 0 {
+		e.millerLoop(f, pairs)
+	}
+	return f, nil
+}
+
+// FinalExponentiation raises given Miller loop output to (p^12 - 1) / q and returns the result
+// as a new target group element.
+func (e *Engine) FinalExponentiation(f *E) *E {
+	r := new(E).Set(f)
+	e.finalExp(r)
+	return r
+}
+
+func (e *Engine) calculateParallel(workers int, finalExp func(f *fe12)) *fe12 {
+	f := e.fp12.one()
+	if len(e.pairs) == 0 {
+		return f
+	}
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	if workers > len(e.pairs) {
+		workers = len(e.pairs)
+	}
+	// each worker has its own engine since engines are not safe for concurrent use
+	results := make([]fe12, workers)
+	var wg sync.WaitGroup
+	n := len(e.pairs)
+	for i := 0; i < workers; i++ {
+		from, to := i*n/workers, (i+1)*n/workers
+		wg.Add(1)
+		go func(f *fe12, pairs []pair) {
+			defer wg.Done()
+			w := NewEngine()
+			w.millerLoop(f, pairs)
+		}(&results[i], e.pairs[from:to])
+	}
+	wg.Wait()
+	for i := 0; i < workers; i++ {
+		e.fp12.mulAssign(f, &results[i])
+	}
+	finalExp(f)
+	return f
+}
+
+// CheckParallel computes pairing splitting pairs across given number of workers
+// and checks if result is equal to one. Partial Miller loop outputs are multiplied
+// and a single final exponentiation is applied. If workers is not positive number of CPUs is used.
+func (e *Engine) CheckParallel(workers int) bool {
+	return e.calculateParallel(workers, e.finalExpPowered).isOne()
+}
+
+// ResultParallel computes pairing splitting pairs across given number of workers
+// and returns target group element as result. If workers is not positive number of CPUs is used.
+func (e *Engine) ResultParallel(workers int) *E {
+	r := e.calculateParallel(workers, e.finalExp)
+	e.Reset()
+	return r
+}
+
 // Check computes pairing and checks if result is equal to one
 func (e *Engine) Check() bool {
-	return e.calculate().isOne()
+	return e.calculate(e.finalExpPowered).isOne()
 }
 
 // Result computes pairing and returns target group element as result.
 func (e *Engine) Result() *E {
-	r := e.calculate()
+	r := e.calculate(e.finalExp)
 	e.Reset()
 	return r
 }
@@ -2,6 +2,7 @@ package bn254
 
 import (
 	"bytes"
+	"crypto/rand"
 	"math/big"
 	"testing"
 )
@@ -163,6 +164,205 @@ func TestPairingEmpty(t *testing.T) {
 	}
 }
 
+func TestPairingPrepared(t *testing.T) {
+	bls := NewEngine()
+	g1, g2 := bls.G1, bls.G2
+	for i := 0; i < fuz; i++ {
+		P1, P2 := g1.rand(), g2.rand()
+		prepared := bls.PrepareG2(P2)
+		e0 := bls.AddPair(P1, P2).Result()
+		e1 := bls.AddPairPrepared(P1, prepared).Result()
+		if !e0.Equal(e1) {
+			t.Fatal("bad pairing with prepared point")
+		}
+		// prepared point should be reusable
+		bls.AddPairPrepared(P1, prepared)
+		bls.AddPairPreparedInv(P1, prepared)
+		if !bls.Check() {
+			t.Fatal("bad pairing with reused prepared point")
+		}
+		bls.Reset()
+	}
+	// e(a * G1, b * G2) = e((a * b) * G1, G2)
+	{
+		a, b := randScalar(q), randScalar(q)
+		c := new(big.Int).Mul(a, b)
+		G1, H1, H2 := g1.One(), g1.One(), g2.One()
+		g1.MulScalar(G1, G1, c)
+		g1.MulScalar(H1, H1, a)
+		g2.MulScalar(H2, H2, b)
+		bls.AddPairPrepared(G1, bls.PrepareG2(g2.One()))
+		bls.AddPairInv(H1, H2)
+		if !bls.Check() {
+			t.Fatal("bad pairing with mixed prepared and non prepared points")
+		}
+		bls.Reset()
+	}
+	// e(G1, 0) == 1
+	{
+		bls.AddPairPrepared(g1.One(), bls.PrepareG2(g2.Zero()))
+		if !bls.Result().IsOne() {
+			t.Fatal("pairing result is expected to be one")
+		}
+	}
+}
+
+func TestPairingMillerLoop(t *testing.T) {
+	bls := NewEngine()
+	g1, g2, gt := bls.G1, bls.G2, bls.GT()
+	P1, P2, H1, H2 := g1.rand(), g2.rand(), g1.rand(), g2.rand()
+	expected := bls.AddPair(P1, P2).AddPair(H1, H2).Result()
+	// two partial Miller loops and a single final exponentiation
+	f0, err := bls.MillerLoop([]*PointG1{P1}, []*PointG2{P2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f1, err := bls.MillerLoopPrepared([]*PointG1{H1}, []*PreparedG2{bls.PrepareG2(H2)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	gt.Mul(f0, f0, f1)
+	f := new(E).Set(f0)
+	result := bls.FinalExponentiation(f0)
+	if !result.Equal(expected) {
+		t.Fatal("bad pairing with partial miller loops")
+	}
+	if !f0.Equal(f) {
+		t.Fatal("final exponentiation should not modify its input")
+	}
+	// pair stack is left as it is
+	bls.AddPair(P1, P2)
+	if _, err := bls.MillerLoop([]*PointG1{H1}, []*PointG2{H2}); err != nil {
+		t.Fatal(err)
+	}
+	if !bls.Result().Equal(bls.FinalExponentiation(mustMillerLoop(t, bls, P1, P2))) {
+		t.Fatal("miller loop should not modify pair stack")
+	}
+	empty, _ := bls.MillerLoop(nil, nil)
+	if !empty.IsOne() {
+		t.Fatal("empty miller loop should be one")
+	}
+	zero, _ := bls.MillerLoop([]*PointG1{g1.Zero()}, []*PointG2{P2})
+	if !zero.IsOne() {
+		t.Fatal("miller loop with point at infinity should be one")
+	}
+	if _, err := bls.MillerLoop([]*PointG1{P1}, nil); err == nil {
+		t.Fatal("length mismatch should be rejected")
+	}
+	if _, err := bls.MillerLoopPrepared(nil, []*PreparedG2{bls.PrepareG2(P2)}); err == nil {
+		t.Fatal("length mismatch should be rejected")
+	}
+	if !bls.FinalExponentiation(gt.New()).IsOne() {
+		t.Fatal("final exponentiation of one should be one")
+	}
+}
+
+func mustMillerLoop(t testing.TB, e *Engine, p1 *PointG1, p2 *PointG2) *E {
+	f, err := e.MillerLoop([]*PointG1{p1}, []*PointG2{p2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+func TestPairingParallel(t *testing.T) {
+	bls := NewEngine()
+	g1, g2 := bls.G1, bls.G2
+	for _, numOfPair := range []int{1, 2, 5, 17} {
+		for _, workers := range []int{0, 1, 3, 4, 32} {
+			targetExp := new(big.Int)
+			var g1s []*PointG1
+			var g2s []*PointG2
+			for i := 0; i < numOfPair; i++ {
+				a1, a2 := randScalar(q), randScalar(q)
+				P1, P2 := g1.One(), g2.One()
+				g1.MulScalar(P1, P1, a1)
+				g2.MulScalar(P2, P2, a2)
+				g1s, g2s = append(g1s, P1), append(g2s, P2)
+				a1.Mul(a1, a2)
+				targetExp.Add(targetExp, a1)
+			}
+			for i := 0; i < numOfPair; i++ {
+				bls.AddPair(g1s[i], g2s[i])
+			}
+			expected := bls.Result()
+			for i := 0; i < numOfPair; i++ {
+				bls.AddPair(g1s[i], g2s[i])
+			}
+			if !bls.ResultParallel(workers).Equal(expected) {
+				t.Fatal("bad parallel pairing result")
+			}
+			for i := 0; i < numOfPair; i++ {
+				bls.AddPair(g1s[i], g2s[i])
+			}
+			T1 := g1.One()
+			g1.MulScalar(T1, T1, targetExp)
+			bls.AddPairInv(T1, g2.One())
+			if !bls.CheckParallel(workers) {
+				t.Fatal("fail parallel multi pairing")
+			}
+			bls.Reset()
+		}
+	}
+	if !bls.CheckParallel(0) {
+		t.Fatal("empty check should be accepted")
+	}
+}
+
+func TestPairingFinalExpPowered(t *testing.T) {
+	e := NewEngine()
+	// m = 2u(6u^2 + 3u + 1)
+	m := new(big.Int).Mul(u, u)
+	m.Mul(m, big.NewInt(6))
+	m.Add(m, new(big.Int).Mul(u, big.NewInt(3)))
+	m.Add(m, big.NewInt(1))
+	m.Mul(m, new(big.Int).Lsh(u, 1))
+	if new(big.Int).GCD(nil, nil, m, q).Cmp(big.NewInt(1)) != 0 {
+		t.Fatal("m should be coprime to q")
+	}
+	for i := 0; i < fuz; i++ {
+		f := mustMillerLoop(t, e, e.G1.rand(), e.G2.rand())
+		f0, f1 := new(fe12).set(f), new(fe12).set(f)
+		e.finalExp(f0)
+		e.finalExpPowered(f1)
+		e.fp12.cyclotomicExp(f0, f0, m)
+		if !f0.equal(f1) {
+			t.Fatal("powered final exponentiation should be equal to m-th power of pairing")
+		}
+		// random element not in cyclotomic subgroup
+		g, _ := new(fe12).rand(rand.Reader)
+		g0, g1 := new(fe12).set(g), new(fe12).set(g)
+		e.finalExp(g0)
+		e.finalExpPowered(g1)
+		e.fp12.cyclotomicExp(g0, g0, m)
+		if !g0.equal(g1) {
+			t.Fatal("powered final exponentiation should be equal to m-th power of pairing")
+		}
+	}
+}
+
+func BenchmarkFinalExp(t *testing.B) {
+	e := NewEngine()
+	f := mustMillerLoop(t, e, e.G1.One(), e.G2.One())
+	r := new(fe12)
+	t.ResetTimer()
+	for i := 0; i < t.N; i++ {
+		r.set(f)
+		e.finalExp(r)
+	}
+}
+
+func BenchmarkFinalExpPowered(t *testing.B) {
+	e := NewEngine()
+	f := mustMillerLoop(t, e, e.G1.One(), e.G2.One())
+	r := new(fe12)
+	t.ResetTimer()
+	for i := 0; i < t.N; i++ {
+		r.set(f)
+		e.finalExpPowered(r)
+	}
+}
+
 func BenchmarkPairing(t *testing.B) {
 	bls := NewEngine()
 	g1, g2, gt := bls.G1, bls.G2, bls.GT()
@@ -170,7 +370,32 @@ func BenchmarkPairing(t *testing.B) {
 	e := gt.New()
 	t.ResetTimer()
 	for i := 0; i < t.N; i++ {
-		e = bls.calculate()
+		e = bls.calculate(bls.finalExp)
+	}
+	_ = e
+}
+
+func BenchmarkPairingPrepared(t *testing.B) {
+	bls := NewEngine()
+	g1, g2, gt := bls.G1, bls.G2, bls.GT()
+	prepared := bls.PrepareG2(g2.One())
+	bls.AddPairPrepared(g1.One(), prepared)
+	e := gt.New()
+	t.ResetTimer()
+	for i := 0; i < t.N; i++ {
+		e = bls.calculate(bls.finalExp)
 	}
 	_ = e
 }
+
+func BenchmarkPairingParallel(t *testing.B) {
+	bls := NewEngine()
+	g1, g2 := bls.G1, bls.G2
+	for i := 0; i < 100; i++ {
+		bls.AddPair(g1.rand(), g2.rand())
+	}
+	t.ResetTimer()
+	for i := 0; i < t.N; i++ {
+		_ = bls.CheckParallel(0)
+	}
+}
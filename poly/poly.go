@@ -0,0 +1,297 @@
+// Package poly implements dense univariate polynomials over the scalar field of BN254.
+package poly
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/kilic/bn254"
+	"github.com/kilic/bn254/fft"
+)
+
+type Fr = bn254.Fr
+
+// mulThreshold is the length of the shorter operand from which multiplication is done with
+// FFT rather than schoolbook method.
+const mulThreshold = 64
+
+// Polynomial is a dense polynomial, i-th element is the coefficient of X^i.
+type Polynomial []Fr
+
+// Degree returns the degree of the polynomial, degree of the zero polynomial is -1.
+func (p Polynomial) Degree() int {
+	for i := len(p) - 1; i >= 0; i-- {
+		if !p[i].IsZero() {
+			return i
+		}
+	}
+	return -1
+}
+
+// Clone returns a copy of the polynomial.
+func (p Polynomial) Clone() Polynomial {
+	return append(Polynomial{}, p...)
+}
+
+// Equal returns true if polynomials are equal. Leading zero coefficients are ignored.
+func (p Polynomial) Equal(q Polynomial) bool {
+	d := p.Degree()
+	if d != q.Degree() {
+		return false
+	}
+	for i := 0; i <= d; i++ {
+		if !p[i].Equal(&q[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Evaluate returns p(x).
+func (p Polynomial) Evaluate(x *Fr) *Fr {
+	r := new(Fr)
+	for i := len(p) - 1; i >= 0; i-- {
+		r.Mul(r, x)
+		r.Add(r, &p[i])
+	}
+	return r
+}
+
+// Big returns coefficients as integers so that a commitment can be computed with
+// G1.MultiExp.
+func (p Polynomial) Big() []*big.Int {
+	r := make([]*big.Int, len(p))
+	for i := range p {
+		r[i] = p[i].Big()
+	}
+	return r
+}
+
+// Add returns a + b.
+func Add(a, b Polynomial) Polynomial {
+	if len(a) < len(b) {
+		a, b = b, a
+	}
+	r := a.Clone()
+	for i := range b {
+		r[i].Add(&r[i], &b[i])
+	}
+	return r
+}
+
+// Sub returns a - b.
+func Sub(a, b Polynomial) Polynomial {
+	n := len(a)
+	if len(b) > n {
+		n = len(b)
+	}
+	r := make(Polynomial, n)
+	copy(r, a)
+	for i := range b {
+		r[i].Sub(&r[i], &b[i])
+	}
+	return r
+}
+
+// Scale returns s * a.
+func Scale(a Polynomial, s *Fr) Polynomial {
+	r := make(Polynomial, len(a))
+	for i := range a {
+		r[i].Mul(&a[i], s)
+	}
+	return r
+}
+
+// Mul returns a * b. Operands longer than a threshold are multiplied by pointwise products
+// of their evaluations, an error is returned if the product does not fit into the largest
+// evaluation domain.
+func Mul(a, b Polynomial) (Polynomial, error) {
+	if len(a) == 0 || len(b) == 0 {
+		return Polynomial{}, nil
+	}
+	n := len(a) + len(b) - 1
+	if len(a) < mulThreshold || len(b) < mulThreshold {
+		r := make(Polynomial, n)
+		t := new(Fr)
+		for i := range a {
+			for j := range b {
+				t.Mul(&a[i], &b[j])
+				r[i+j].Add(&r[i+j], t)
+			}
+		}
+		return r, nil
+	}
+	size := 1
+	for size < n {
+		size <<= 1
+	}
+	d, err := fft.NewDomain(size)
+	if err != nil {
+		return nil, err
+	}
+	ea, eb := make(Polynomial, size), make(Polynomial, size)
+	copy(ea, a)
+	copy(eb, b)
+	if err := d.FFT(ea); err != nil {
+		return nil, err
+	}
+	if err := d.FFT(eb); err != nil {
+		return nil, err
+	}
+	for i := range ea {
+		ea[i].Mul(&ea[i], &eb[i])
+	}
+	if err := d.InverseFFT(ea); err != nil {
+		return nil, err
+	}
+	return ea[:n], nil
+}
+
+// DivideByLinear returns the quotient and the remainder of division of p by X - z. Remainder is
+// equal to p(z).
+func DivideByLinear(p Polynomial, z *Fr) (Polynomial, *Fr) {
+	if len(p) == 0 {
+		return Polynomial{}, new(Fr)
+	}
+	// synthetic division from the leading coefficient
+	q := make(Polynomial, len(p)-1)
+	r := new(Fr).Set(&p[len(p)-1])
+	for i := len(p) - 2; i >= 0; i-- {
+		q[i].Set(r)
+		r.Mul(r, z)
+		r.Add(r, &p[i])
+	}
+	return q, r
+}
+
+// DivideByVanishing returns the quotient and the remainder of division of p by the vanishing
+// polynomial X^n - 1 of the domain of size n. Remainder has n coefficients, n should be positive.
+func DivideByVanishing(p Polynomial, n int) (Polynomial, Polynomial, error) {
+	if n <= 0 {
+		return nil, nil, errors.New("degree of vanishing polynomial should be positive")
+	}
+	r := make(Polynomial, n)
+	if len(p) <= n {
+		copy(r, p)
+		return Polynomial{}, r, nil
+	}
+	t := p.Clone()
+	q := make(Polynomial, len(p)-n)
+	for i := len(p) - 1; i >= n; i-- {
+		// X^i = X^(i-n) * (X^n - 1) + X^(i-n)
+		q[i-n].Set(&t[i])
+		t[i-n].Add(&t[i-n], &t[i])
+	}
+	copy(r, t[:n])
+	return q, r, nil
+}
+
+// InterpolateOnDomain returns the polynomial of degree less than domain size that takes values
+// evals at the elements of the domain.
+func InterpolateOnDomain(d *fft.Domain, evals []Fr) (Polynomial, error) {
+	p := append(Polynomial{}, evals...)
+	if err := d.InverseFFT(p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Interpolate returns the polynomial of degree less than len(xs) that takes values ys at
+// points xs. Points should be distinct.
+func Interpolate(xs, ys []Fr) (Polynomial, error) {
+	if len(xs) != len(ys) {
+		return nil, errors.New("point and value vectors should be in same length")
+	}
+	n := len(xs)
+	// z = prod (X - x_i)
+	z := Polynomial{*new(Fr).One()}
+	for i := range xs {
+		var err error
+		z, err = Mul(z, Polynomial{*new(Fr).Neg(&xs[i]), *new(Fr).One()})
+		if err != nil {
+			return nil, err
+		}
+	}
+	// denominators prod_{j != i} (x_i - x_j) are derivatives of z at x_i
+	zd := make(Polynomial, n)
+	for i := 1; i <= n; i++ {
+		zd[i-1].Mul(&z[i], new(Fr).SetUint64(uint64(i)))
+	}
+	den := make([]Fr, n)
+	for i := range xs {
+		den[i].Set(zd.Evaluate(&xs[i]))
+		if den[i].IsZero() {
+			return nil, errors.New("points should be distinct")
+		}
+	}
+	batchInverse(den)
+	r := make(Polynomial, n)
+	for i := range xs {
+		// z / (X - x_i) is the numerator of i-th Lagrange polynomial
+		l, _ := DivideByLinear(z, &xs[i])
+		c := new(Fr).Mul(&ys[i], &den[i])
+		for j := range l {
+			l[j].Mul(&l[j], c)
+			r[j].Add(&r[j], &l[j])
+		}
+	}
+	return r, nil
+}
+
+// LagrangeBasisEvaluations returns evaluations of the Lagrange basis polynomials of the domain
+// at z. Evaluation of i-th polynomial is w^i * (z^n - 1) / (n * (z - w^i)).
+func LagrangeBasisEvaluations(d *fft.Domain, z *Fr) []Fr {
+	r := make([]Fr, d.Size)
+	zn := new(Fr).Set(z)
+	for i := uint(0); i < d.LogSize; i++ {
+		zn.Square(zn)
+	}
+	zn.Sub(zn, new(Fr).One())
+	w := new(Fr).One()
+	if zn.IsZero() {
+		// z is in the domain
+		for i := range r {
+			if w.Equal(z) {
+				r[i].One()
+				break
+			}
+			w.Mul(w, &d.Generator)
+		}
+		return r
+	}
+	den := make([]Fr, d.Size)
+	for i := range den {
+		den[i].Sub(z, w)
+		w.Mul(w, &d.Generator)
+	}
+	batchInverse(den)
+	zn.Mul(zn, &d.SizeInv)
+	w.One()
+	for i := range r {
+		r[i].Mul(zn, w)
+		r[i].Mul(&r[i], &den[i])
+		w.Mul(w, &d.Generator)
+	}
+	return r
+}
+
+// batchInverse inverts each element of a in place with a single inversion. Elements should be
+// non zero.
+func batchInverse(a []Fr) {
+	if len(a) == 0 {
+		return
+	}
+	acc := make([]Fr, len(a))
+	acc[0].Set(&a[0])
+	for i := 1; i < len(a); i++ {
+		acc[i].Mul(&acc[i-1], &a[i])
+	}
+	inv := new(Fr).Inverse(&acc[len(a)-1])
+	t := new(Fr)
+	for i := len(a) - 1; i > 0; i-- {
+		t.Mul(inv, &acc[i-1])
+		inv.Mul(inv, &a[i])
+		a[i].Set(t)
+	}
+	a[0].Set(inv)
+}
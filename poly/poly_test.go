@@ -0,0 +1,220 @@
+package poly
+
+import (
+	"crypto/rand"
+	"testing"
+
+	"github.com/kilic/bn254"
+	"github.com/kilic/bn254/fft"
+)
+
+func randFr() *Fr {
+	a, err := new(Fr).Rand(rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
+
+func randPoly(n int) Polynomial {
+	p := make(Polynomial, n)
+	for i := range p {
+		p[i].Set(randFr())
+	}
+	return p
+}
+
+func mul(t *testing.T, a, b Polynomial) Polynomial {
+	r, err := Mul(a, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func TestPolynomialBasics(t *testing.T) {
+	if (Polynomial{}).Degree() != -1 || (Polynomial{Fr{}, Fr{}}).Degree() != -1 {
+		t.Fatal("degree of zero polynomial is -1")
+	}
+	p := randPoly(10)
+	q := append(p.Clone(), Fr{}, Fr{})
+	if p.Degree() != 9 || q.Degree() != 9 {
+		t.Fatal("bad degree")
+	}
+	if !p.Equal(q) {
+		t.Fatal("leading zeros should be ignored")
+	}
+	q[0].Add(&q[0], new(Fr).One())
+	if p.Equal(q) {
+		t.Fatal("polynomials are not equal")
+	}
+	big := p.Big()
+	for i := range p {
+		if !new(Fr).SetBig(big[i]).Equal(&p[i]) {
+			t.Fatal("bad integer conversion")
+		}
+	}
+}
+
+func TestPolynomialArithmetic(t *testing.T) {
+	for _, sizes := range [][2]int{{0, 3}, {1, 1}, {5, 17}, {63, 64}, {64, 64}, {100, 200}} {
+		a, b := randPoly(sizes[0]), randPoly(sizes[1])
+		x := randFr()
+		ax, bx := a.Evaluate(x), b.Evaluate(x)
+		if !Add(a, b).Evaluate(x).Equal(new(Fr).Add(ax, bx)) {
+			t.Fatal("(a + b)(x) == a(x) + b(x)")
+		}
+		if !Sub(a, b).Evaluate(x).Equal(new(Fr).Sub(ax, bx)) {
+			t.Fatal("(a - b)(x) == a(x) - b(x)")
+		}
+		if !mul(t, a, b).Evaluate(x).Equal(new(Fr).Mul(ax, bx)) {
+			t.Fatal("(a * b)(x) == a(x) * b(x)")
+		}
+		if !Scale(a, x).Evaluate(x).Equal(new(Fr).Mul(ax, x)) {
+			t.Fatal("(s * a)(x) == s * a(x)")
+		}
+		if len(a) > 0 && len(b) > 0 && len(mul(t, a, b)) != len(a)+len(b)-1 {
+			t.Fatal("bad product length")
+		}
+	}
+	// fft multiplication agrees with schoolbook
+	a, b := randPoly(mulThreshold), randPoly(3*mulThreshold)
+	c := make(Polynomial, len(a)+len(b)-1)
+	for i := range a {
+		for j := range b {
+			c[i+j].Add(&c[i+j], new(Fr).Mul(&a[i], &b[j]))
+		}
+	}
+	if !mul(t, a, b).Equal(c) {
+		t.Fatal("bad fft multiplication")
+	}
+}
+
+func TestPolynomialDivision(t *testing.T) {
+	for _, n := range []int{0, 1, 2, 33} {
+		p, z := randPoly(n), randFr()
+		q, r := DivideByLinear(p, z)
+		if !r.Equal(p.Evaluate(z)) {
+			t.Fatal("remainder is p(z)")
+		}
+		// p == q * (X - z) + r
+		e := Add(mul(t, q, Polynomial{*new(Fr).Neg(z), *new(Fr).One()}), Polynomial{*r})
+		if !e.Equal(p) {
+			t.Fatal("bad division by linear polynomial")
+		}
+	}
+	for _, sizes := range [][2]int{{3, 8}, {8, 8}, {20, 8}, {100, 16}} {
+		p, n := randPoly(sizes[0]), sizes[1]
+		q, r, err := DivideByVanishing(p, n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(r) != n {
+			t.Fatal("bad remainder length")
+		}
+		z := make(Polynomial, n+1)
+		z[0].Neg(new(Fr).One())
+		z[n].One()
+		if !Add(mul(t, q, z), r).Equal(p) {
+			t.Fatal("bad division by vanishing polynomial")
+		}
+		d, _ := fft.NewDomain(n)
+		for i := 0; i < n; i++ {
+			if !p.Evaluate(d.Element(i)).Equal(r.Evaluate(d.Element(i))) {
+				t.Fatal("remainder agrees with polynomial over the domain")
+			}
+		}
+	}
+	for _, n := range []int{0, -1} {
+		if _, _, err := DivideByVanishing(randPoly(4), n); err == nil {
+			t.Fatal("non positive degree should be rejected")
+		}
+	}
+}
+
+func TestInterpolation(t *testing.T) {
+	d, _ := fft.NewDomain(16)
+	p := randPoly(16)
+	evals := make([]Fr, d.Size)
+	xs := make([]Fr, d.Size)
+	for i := range evals {
+		xs[i].Set(d.Element(i))
+		evals[i].Set(p.Evaluate(&xs[i]))
+	}
+	q, err := InterpolateOnDomain(d, evals)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !q.Equal(p) {
+		t.Fatal("bad interpolation on domain")
+	}
+	// arbitrary points
+	p = randPoly(7)
+	xs, ys := make([]Fr, 7), make([]Fr, 7)
+	for i := range xs {
+		xs[i].Set(randFr())
+		ys[i].Set(p.Evaluate(&xs[i]))
+	}
+	q, err = Interpolate(xs, ys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !q.Equal(p) {
+		t.Fatal("bad interpolation")
+	}
+	xs[1].Set(&xs[0])
+	if _, err := Interpolate(xs, ys); err == nil {
+		t.Fatal("points should be distinct")
+	}
+	if _, err := Interpolate(xs, ys[1:]); err == nil {
+		t.Fatal("vectors should be in same length")
+	}
+}
+
+func TestLagrangeBasisEvaluations(t *testing.T) {
+	d, _ := fft.NewDomain(32)
+	p := randPoly(32)
+	evals := make([]Fr, d.Size)
+	for i := range evals {
+		evals[i].Set(p.Evaluate(d.Element(i)))
+	}
+	for _, z := range []*Fr{randFr(), d.Element(5)} {
+		l := LagrangeBasisEvaluations(d, z)
+		// p(z) == sum p(w^i) * L_i(z)
+		acc := new(Fr)
+		for i := range l {
+			acc.Add(acc, new(Fr).Mul(&evals[i], &l[i]))
+		}
+		if !acc.Equal(p.Evaluate(z)) {
+			t.Fatal("bad lagrange basis evaluations")
+		}
+	}
+}
+
+func TestCommitWithMultiExp(t *testing.T) {
+	// commitment to p with powers of tau is tau^i * G summed with coefficients of p
+	g := bn254.NewG1()
+	tau, p := randFr(), randPoly(8)
+	bases := make([]*bn254.PointG1, len(p))
+	w := new(Fr).One()
+	for i := range bases {
+		bases[i] = g.MulScalar(g.New(), g.One(), w.Big())
+		w.Mul(w, tau)
+	}
+	c, err := g.MultiExp(g.New(), bases, p.Big())
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := g.MulScalar(g.New(), g.One(), p.Evaluate(tau).Big())
+	if !g.Equal(c, expected) {
+		t.Fatal("commitment is p(tau) * G")
+	}
+}
+
+func BenchmarkMul(t *testing.B) {
+	a, b := randPoly(1<<12), randPoly(1<<12)
+	t.ResetTimer()
+	for i := 0; i < t.N; i++ {
+		_, _ = Mul(a, b)
+	}
+}
@@ -0,0 +1,119 @@
+// Package precompile implements Ethereum alt_bn128 precompiled contracts
+// ecAdd (0x06), ecMul (0x07) and ecPairing (0x08) as specified in EIP-196 and EIP-197
+// with gas costs of EIP-1108.
+//
+// Field elements are 32 byte big endian words, point at infinity is encoded as (0, 0),
+// G2 coordinates are encoded imaginary part first. Short inputs of ecAdd and ecMul are
+// right padded with zeros and excess bytes are ignored.
+package precompile
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/kilic/bn254"
+)
+
+// Gas costs of precompiles as of EIP-1108.
+const (
+	AddGas             uint64 = 150
+	MulGas             uint64 = 6000
+	PairingBaseGas     uint64 = 45000
+	PairingPerPointGas uint64 = 34000
+)
+
+const (
+	wordSize        = 32
+	g1Size          = 2 * wordSize
+	g2Size          = 4 * wordSize
+	addInputSize    = 2 * g1Size
+	mulInputSize    = g1Size + wordSize
+	pairingPairSize = g1Size + g2Size
+)
+
+var (
+	errBadPairingInput = errors.New("bad elliptic curve pairing size")
+	errNotInSubgroup   = errors.New("g2 point is not in correct subgroup")
+)
+
+// AddRequiredGas returns gas cost of ecAdd.
+func AddRequiredGas(input []byte) uint64 {
+	return AddGas
+}
+
+// MulRequiredGas returns gas cost of ecMul.
+func MulRequiredGas(input []byte) uint64 {
+	return MulGas
+}
+
+// PairingRequiredGas returns gas cost of ecPairing for given input.
+func PairingRequiredGas(input []byte) uint64 {
+	return PairingBaseGas + uint64(len(input)/pairingPairSize)*PairingPerPointGas
+}
+
+// Add implements ecAdd precompile at address 0x06.
+// Input is two G1 points and output is their sum in 64 bytes.
+func Add(input []byte) ([]byte, error) {
+	input = rightPad(input, addInputSize)
+	g := bn254.NewG1()
+	p0, err := g.FromBytes(input[:g1Size])
+	if err != nil {
+		return nil, err
+	}
+	p1, err := g.FromBytes(input[g1Size:addInputSize])
+	if err != nil {
+		return nil, err
+	}
+	g.Add(p0, p0, p1)
+	return g.ToBytes(p0), nil
+}
+
+// Mul implements ecMul precompile at address 0x07.
+// Input is a G1 point followed by a 32 byte scalar and output is the product in 64 bytes.
+func Mul(input []byte) ([]byte, error) {
+	input = rightPad(input, mulInputSize)
+	g := bn254.NewG1()
+	p, err := g.FromBytes(input[:g1Size])
+	if err != nil {
+		return nil, err
+	}
+	g.MulScalar(p, p, new(big.Int).SetBytes(input[g1Size:mulInputSize]))
+	return g.ToBytes(p), nil
+}
+
+// Pairing implements ecPairing precompile at address 0x08.
+// Input is a list of G1 and G2 point pairs. Output is a 32 byte word
+// which is one if product of pairings is equal to one, zero otherwise.
+// Empty input is accepted.
+func Pairing(input []byte) ([]byte, error) {
+	if len(input)%pairingPairSize != 0 {
+		return nil, errBadPairingInput
+	}
+	e := bn254.NewEngine()
+	for i := 0; i < len(input); i += pairingPairSize {
+		p1, err := e.G1.FromBytes(input[i : i+g1Size])
+		if err != nil {
+			return nil, err
+		}
+		p2, err := e.G2.FromBytes(input[i+g1Size : i+pairingPairSize])
+		if err != nil {
+			return nil, err
+		}
+		if !e.G2.InCorrectSubgroup(p2) {
+			return nil, errNotInSubgroup
+		}
+		e.AddPair(p1, p2)
+	}
+	out := make([]byte, wordSize)
+	if e.Check() {
+		out[wordSize-1] = 1
+	}
+	return out, nil
+}
+
+// rightPad returns a slice of given size that is input truncated or padded with zeros.
+func rightPad(input []byte, size int) []byte {
+	out := make([]byte, size)
+	copy(out, input)
+	return out
+}
@@ -0,0 +1,155 @@
+package ptau
+
+import (
+	"math/big"
+	"runtime"
+	"sync"
+
+	"github.com/kilic/bn254"
+	"github.com/kilic/bn254/fft"
+)
+
+// ToLagrangeG1 converts powers of tau [G, tau * G, ..., tau^(n-1) * G] to evaluations of the
+// Lagrange basis polynomials of the domain of size n at tau, [L_0(tau) * G, ...,
+// L_(n-1)(tau) * G]. It is the inverse FFT of the powers carried out in the group. Number of
+// points should be a power of two.
+func ToLagrangeG1(powers []*bn254.PointG1) ([]*bn254.PointG1, error) {
+	d, err := fft.NewDomain(len(powers))
+	if err != nil {
+		return nil, err
+	}
+	n := d.Size
+	g := bn254.NewG1()
+	a := make([]*bn254.PointG1, n)
+	for i := range powers {
+		a[i] = g.New().Set(powers[i])
+	}
+	difG1(a, twiddles(d), 1)
+	inverseOrder(n, func(i, j int) { a[i], a[j] = a[j], a[i] })
+	parallel(n, func(start, end int) {
+		g, s := bn254.NewG1(), d.SizeInv.Big()
+		for i := start; i < end; i++ {
+			g.MulScalar(a[i], a[i], s)
+		}
+	})
+	return a, nil
+}
+
+// ToLagrangeG2 is ToLagrangeG1 for points in G2.
+func ToLagrangeG2(powers []*bn254.PointG2) ([]*bn254.PointG2, error) {
+	d, err := fft.NewDomain(len(powers))
+	if err != nil {
+		return nil, err
+	}
+	n := d.Size
+	g := bn254.NewG2()
+	a := make([]*bn254.PointG2, n)
+	for i := range powers {
+		a[i] = g.New().Set(powers[i])
+	}
+	difG2(a, twiddles(d), 1)
+	inverseOrder(n, func(i, j int) { a[i], a[j] = a[j], a[i] })
+	parallel(n, func(start, end int) {
+		g, s := bn254.NewG2(), d.SizeInv.Big()
+		for i := start; i < end; i++ {
+			g.MulScalar(a[i], a[i], s)
+		}
+	})
+	return a, nil
+}
+
+func twiddles(d *fft.Domain) []*big.Int {
+	t := make([]*big.Int, d.Size/2)
+	for i := range t {
+		t[i] = d.Element(i).Big()
+	}
+	return t
+}
+
+// difG1 is the decimation in frequency transform of group elements, output is in bit
+// reversed order.
+func difG1(a []*bn254.PointG1, twiddles []*big.Int, stride int) {
+	n := len(a)
+	if n < 2 {
+		return
+	}
+	m := n / 2
+	parallel(m, func(start, end int) {
+		g := bn254.NewG1()
+		t := g.New()
+		for j := start; j < end; j++ {
+			g.Sub(t, a[j], a[j+m])
+			g.Add(a[j], a[j], a[j+m])
+			if j == 0 {
+				a[j+m].Set(t)
+				continue
+			}
+			g.MulScalar(a[j+m], t, twiddles[j*stride])
+		}
+	})
+	difG1(a[:m], twiddles, stride*2)
+	difG1(a[m:], twiddles, stride*2)
+}
+
+// difG2 is difG1 for points in G2.
+func difG2(a []*bn254.PointG2, twiddles []*big.Int, stride int) {
+	n := len(a)
+	if n < 2 {
+		return
+	}
+	m := n / 2
+	parallel(m, func(start, end int) {
+		g := bn254.NewG2()
+		t := g.New()
+		for j := start; j < end; j++ {
+			g.Sub(t, a[j], a[j+m])
+			g.Add(a[j], a[j], a[j+m])
+			if j == 0 {
+				a[j+m].Set(t)
+				continue
+			}
+			g.MulScalar(a[j+m], t, twiddles[j*stride])
+		}
+	})
+	difG2(a[:m], twiddles, stride*2)
+	difG2(a[m:], twiddles, stride*2)
+}
+
+// parallel splits [0, n) into ranges which are processed concurrently. Workers should use
+// their own group instances since group instances hold scratch space.
+func parallel(n int, fn func(start, end int)) {
+	workers := runtime.NumCPU()
+	chunk := (n + workers - 1) / workers
+	var wg sync.WaitGroup
+	for start := 0; start < n; start += chunk {
+		end := start + chunk
+		if end > n {
+			end = n
+		}
+		wg.Add(1)
+		go func(start, end int) {
+			fn(start, end)
+			wg.Done()
+		}(start, end)
+	}
+	wg.Wait()
+}
+
+// inverseOrder brings output of the forward transform from bit reversed order into the order
+// of the inverse transform, since transform with the inverse generator is the forward
+// transform with indexes negated.
+func inverseOrder(n int, swap func(i, j int)) {
+	for i, j := 1, 0; i < n; i++ {
+		bit := n >> 1
+		for ; j&bit != 0; bit >>= 1 {
+			j ^= bit
+		}
+		j ^= bit
+		if i < j {
+			swap(i, j)
+		}
+	}
+	for i, j := 1, n-1; i < j; i, j = i+1, j-1 {
+		swap(i, j)
+	}
+}
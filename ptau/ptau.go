@@ -0,0 +1,363 @@
+// Package ptau reads powers of tau ceremony files in the format of snarkjs.
+//
+// A file starts with the magic "ptau", a version and the number of sections. Each section has
+// a type, a byte size and its data. Field elements are 32 bytes in little endian Montgomery
+// form, G1 points are x, y and G2 points are x0, x1, y0, y1 where x = x0 + x1 * u.
+package ptau
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"sort"
+
+	"github.com/kilic/bn254"
+)
+
+// Section types of a ptau file.
+const (
+	SectionHeader             = 1
+	SectionTauG1              = 2
+	SectionTauG2              = 3
+	SectionAlphaTauG1         = 4
+	SectionBetaTauG1          = 5
+	SectionBetaG2             = 6
+	SectionContributions      = 7
+	SectionLagrangeTauG1      = 12
+	SectionLagrangeTauG2      = 13
+	SectionLagrangeAlphaTauG1 = 14
+	SectionLagrangeBetaTauG1  = 15
+)
+
+const (
+	g1Size = 64
+	g2Size = 128
+)
+
+// bufferSize is the size of read buffer while streaming points of a section.
+const bufferSize = 1 << 16
+
+var magic = []byte("ptau")
+
+// modulus is the base field modulus expected in the header.
+var modulus, _ = new(big.Int).SetString("30644e72e131a029b85045b68181585d97816a916871ca8d3c208c16d87cfd47", 16)
+
+// Header is the content of the header section.
+type Header struct {
+	// Power is the log size of the ceremony, tau G1 section has 2^(Power+1) - 1 points and
+	// other sections of powers have 2^Power points.
+	Power         uint32
+	CeremonyPower uint32
+}
+
+type section struct {
+	offset int64
+	size   uint64
+}
+
+// Reader reads points of a ptau file. Sections are read on demand so only requested points
+// are loaded into memory.
+type Reader struct {
+	r        io.ReadSeeker
+	sections map[uint32]section
+	Header   Header
+	validate bool
+}
+
+// NewReader parses the section table and the header of a ptau file. If validate is set each
+// point is checked to be on curve and in the correct subgroup.
+func NewReader(r io.ReadSeeker, validate bool) (*Reader, error) {
+	var head [12]byte
+	if _, err := io.ReadFull(r, head[:]); err != nil {
+		return nil, err
+	}
+	if string(head[:4]) != string(magic) {
+		return nil, errors.New("not a ptau file")
+	}
+	if version := binary.LittleEndian.Uint32(head[4:]); version != 1 {
+		return nil, fmt.Errorf("unsupported version %d", version)
+	}
+	n := binary.LittleEndian.Uint32(head[8:])
+	reader := &Reader{r: r, sections: make(map[uint32]section), validate: validate}
+	offset := int64(len(head))
+	for i := uint32(0); i < n; i++ {
+		var s [12]byte
+		if _, err := io.ReadFull(r, s[:]); err != nil {
+			return nil, err
+		}
+		typ := binary.LittleEndian.Uint32(s[:])
+		if _, ok := reader.sections[typ]; ok {
+			return nil, fmt.Errorf("duplicate section %d", typ)
+		}
+		size := binary.LittleEndian.Uint64(s[4:])
+		offset += int64(len(s))
+		reader.sections[typ] = section{offset, size}
+		offset += int64(size)
+		if _, err := r.Seek(offset, io.SeekStart); err != nil {
+			return nil, err
+		}
+	}
+	if err := reader.readHeader(); err != nil {
+		return nil, err
+	}
+	return reader, nil
+}
+
+func (r *Reader) readHeader() error {
+	s, ok := r.sections[SectionHeader]
+	if !ok {
+		return errors.New("header section is missing")
+	}
+	if _, err := r.r.Seek(s.offset, io.SeekStart); err != nil {
+		return err
+	}
+	var buf [4]byte
+	if _, err := io.ReadFull(r.r, buf[:]); err != nil {
+		return err
+	}
+	n8 := binary.LittleEndian.Uint32(buf[:])
+	if n8 != 32 || s.size != 4+32+4+4 {
+		return errors.New("bad header size")
+	}
+	q := make([]byte, 32)
+	if _, err := io.ReadFull(r.r, q); err != nil {
+		return err
+	}
+	// modulus is encoded in little endian
+	for i, j := 0, len(q)-1; i < j; i, j = i+1, j-1 {
+		q[i], q[j] = q[j], q[i]
+	}
+	if new(big.Int).SetBytes(q).Cmp(modulus) != 0 {
+		return errors.New("file is not for bn254")
+	}
+	if err := binary.Read(r.r, binary.LittleEndian, &r.Header); err != nil {
+		return err
+	}
+	if r.Header.Power > 28 {
+		return errors.New("power is larger than 2-adicity of scalar field")
+	}
+	return nil
+}
+
+// Sections returns types of sections in the file in ascending order.
+func (r *Reader) Sections() []uint32 {
+	types := make([]uint32, 0, len(r.sections))
+	for typ := range r.sections {
+		types = append(types, typ)
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i] < types[j] })
+	return types
+}
+
+// ReadG1 streams the first n points of a section of G1 points into fn.
+func (r *Reader) ReadG1(typ uint32, n int, fn func(i int, p *bn254.PointG1) error) error {
+	return r.readG1(typ, 0, n, fn)
+}
+
+// ReadG2 streams the first n points of a section of G2 points into fn.
+func (r *Reader) ReadG2(typ uint32, n int, fn func(i int, p *bn254.PointG2) error) error {
+	return r.readG2(typ, 0, n, fn)
+}
+
+// readG1 streams n points of a section into fn after skipping the first skip points.
+func (r *Reader) readG1(typ uint32, skip, n int, fn func(i int, p *bn254.PointG1) error) error {
+	br, err := r.points(typ, skip, n, g1Size)
+	if err != nil {
+		return err
+	}
+	g := bn254.NewG1()
+	buf := make([]byte, g1Size)
+	for i := 0; i < n; i++ {
+		if _, err := io.ReadFull(br, buf); err != nil {
+			return err
+		}
+		p, err := g.FromMontgomeryLE(buf)
+		if err != nil {
+			return err
+		}
+		if r.validate && !g.IsOnCurve(p) {
+			return fmt.Errorf("point %d of section %d is not on curve", skip+i, typ)
+		}
+		if err := fn(i, p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readG2 streams n points of a section into fn after skipping the first skip points.
+func (r *Reader) readG2(typ uint32, skip, n int, fn func(i int, p *bn254.PointG2) error) error {
+	br, err := r.points(typ, skip, n, g2Size)
+	if err != nil {
+		return err
+	}
+	g := bn254.NewG2()
+	buf := make([]byte, g2Size)
+	for i := 0; i < n; i++ {
+		if _, err := io.ReadFull(br, buf); err != nil {
+			return err
+		}
+		p, err := g.FromMontgomeryLE(buf)
+		if err != nil {
+			return err
+		}
+		if r.validate && (!g.IsOnCurve(p) || !g.InCorrectSubgroup(p)) {
+			return fmt.Errorf("point %d of section %d is not in G2", skip+i, typ)
+		}
+		if err := fn(i, p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// points seeks to the point at index skip of a section and returns a buffered reader for
+// following n points of given size.
+func (r *Reader) points(typ uint32, skip, n int, size uint64) (io.Reader, error) {
+	s, ok := r.sections[typ]
+	if !ok {
+		return nil, fmt.Errorf("section %d is missing", typ)
+	}
+	if uint64(skip+n)*size > s.size {
+		return nil, fmt.Errorf("section %d has less than %d points", typ, skip+n)
+	}
+	if _, err := r.r.Seek(s.offset+int64(skip)*int64(size), io.SeekStart); err != nil {
+		return nil, err
+	}
+	return bufio.NewReaderSize(r.r, bufferSize), nil
+}
+
+func (r *Reader) g1Points(typ uint32, n int) ([]*bn254.PointG1, error) {
+	if n < 0 {
+		return nil, errors.New("number of points should not be negative")
+	}
+	points := make([]*bn254.PointG1, n)
+	err := r.ReadG1(typ, n, func(i int, p *bn254.PointG1) error {
+		points[i] = p
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return points, nil
+}
+
+func (r *Reader) g2Points(typ uint32, n int) ([]*bn254.PointG2, error) {
+	if n < 0 {
+		return nil, errors.New("number of points should not be negative")
+	}
+	points := make([]*bn254.PointG2, n)
+	err := r.ReadG2(typ, n, func(i int, p *bn254.PointG2) error {
+		points[i] = p
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return points, nil
+}
+
+// TauG1 returns the first n powers of tau in G1.
+func (r *Reader) TauG1(n int) ([]*bn254.PointG1, error) {
+	return r.g1Points(SectionTauG1, n)
+}
+
+// TauG2 returns the first n powers of tau in G2.
+func (r *Reader) TauG2(n int) ([]*bn254.PointG2, error) {
+	return r.g2Points(SectionTauG2, n)
+}
+
+// AlphaTauG1 returns the first n powers of tau multiplied by alpha in G1.
+func (r *Reader) AlphaTauG1(n int) ([]*bn254.PointG1, error) {
+	return r.g1Points(SectionAlphaTauG1, n)
+}
+
+// BetaTauG1 returns the first n powers of tau multiplied by beta in G1.
+func (r *Reader) BetaTauG1(n int) ([]*bn254.PointG1, error) {
+	return r.g1Points(SectionBetaTauG1, n)
+}
+
+// BetaG2 returns beta in G2.
+func (r *Reader) BetaG2() (*bn254.PointG2, error) {
+	points, err := r.g2Points(SectionBetaG2, 1)
+	if err != nil {
+		return nil, err
+	}
+	return points[0], nil
+}
+
+// LagrangeTauG1 returns evaluations of the Lagrange basis polynomials of the domain of size n at
+// tau in G1. Size should be a power of two not larger than 2^Power. Points are read from the
+// Lagrange section if the file is prepared for phase two, otherwise they are computed from
+// powers of tau with an inverse FFT in the group.
+func (r *Reader) LagrangeTauG1(n int) ([]*bn254.PointG1, error) {
+	return r.lagrangeG1(SectionLagrangeTauG1, SectionTauG1, n)
+}
+
+// LagrangeTauG2 is LagrangeTauG1 in G2.
+func (r *Reader) LagrangeTauG2(n int) ([]*bn254.PointG2, error) {
+	if err := r.checkLagrangeSize(n); err != nil {
+		return nil, err
+	}
+	if _, ok := r.sections[SectionLagrangeTauG2]; ok {
+		points := make([]*bn254.PointG2, n)
+		err := r.readG2(SectionLagrangeTauG2, n-1, n, func(i int, p *bn254.PointG2) error {
+			points[i] = p
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		return points, nil
+	}
+	points, err := r.TauG2(n)
+	if err != nil {
+		return nil, err
+	}
+	return ToLagrangeG2(points)
+}
+
+// LagrangeAlphaTauG1 is LagrangeTauG1 with evaluations multiplied by alpha.
+func (r *Reader) LagrangeAlphaTauG1(n int) ([]*bn254.PointG1, error) {
+	return r.lagrangeG1(SectionLagrangeAlphaTauG1, SectionAlphaTauG1, n)
+}
+
+// LagrangeBetaTauG1 is LagrangeTauG1 with evaluations multiplied by beta.
+func (r *Reader) LagrangeBetaTauG1(n int) ([]*bn254.PointG1, error) {
+	return r.lagrangeG1(SectionLagrangeBetaTauG1, SectionBetaTauG1, n)
+}
+
+func (r *Reader) checkLagrangeSize(n int) error {
+	if n <= 0 || n&(n-1) != 0 || uint64(n) > 1<<r.Header.Power {
+		return errors.New("size should be a power of two not larger than 2^power")
+	}
+	return nil
+}
+
+// lagrangeG1 reads points of domain of size n from a Lagrange section or computes them from
+// the section of powers if the file is not prepared for phase two.
+func (r *Reader) lagrangeG1(typ, powersTyp uint32, n int) ([]*bn254.PointG1, error) {
+	if err := r.checkLagrangeSize(n); err != nil {
+		return nil, err
+	}
+	if _, ok := r.sections[typ]; ok {
+		// points for domain sizes 1, 2, 4, ... follow each other
+		points := make([]*bn254.PointG1, n)
+		err := r.readG1(typ, n-1, n, func(i int, p *bn254.PointG1) error {
+			points[i] = p
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		return points, nil
+	}
+	points, err := r.g1Points(powersTyp, n)
+	if err != nil {
+		return nil, err
+	}
+	return ToLagrangeG1(points)
+}
@@ -0,0 +1,284 @@
+package ptau
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"math/big"
+	"testing"
+
+	"github.com/kilic/bn254"
+	"github.com/kilic/bn254/fft"
+	"github.com/kilic/bn254/poly"
+)
+
+// montgomeryLE encodes 32 bytes big endian field element into little endian Montgomery form.
+func montgomeryLE(in []byte) []byte {
+	r := new(big.Int).Lsh(big.NewInt(1), 256)
+	v := new(big.Int).SetBytes(in)
+	v.Mul(v, r).Mod(v, modulus)
+	out := make([]byte, 32)
+	for i, b := range v.Bytes() {
+		out[len(v.Bytes())-1-i] = b
+	}
+	return out
+}
+
+func encodeG1(p *bn254.PointG1) []byte {
+	in := bn254.NewG1().ToBytes(p)
+	return append(montgomeryLE(in[:32]), montgomeryLE(in[32:])...)
+}
+
+func encodeG2(p *bn254.PointG2) []byte {
+	// ToBytes orders coefficients as x1, x0, y1, y0 while the file expects x0, x1, y0, y1
+	in := bn254.NewG2().ToBytes(p)
+	out := []byte{}
+	for _, i := range []int{1, 0, 3, 2} {
+		out = append(out, montgomeryLE(in[i*32:(i+1)*32])...)
+	}
+	return out
+}
+
+type testCeremony struct {
+	power            uint32
+	tau, alpha, beta *bn254.Fr
+	withLagrange     bool
+	corruptG1        bool
+	nonSubgroupG2    bool
+}
+
+func (c *testCeremony) powers(n int, s *bn254.Fr) []*bn254.Fr {
+	r := make([]*bn254.Fr, n)
+	w := new(bn254.Fr).Set(s)
+	for i := range r {
+		r[i] = new(bn254.Fr).Set(w)
+		w.Mul(w, c.tau)
+	}
+	return r
+}
+
+func (c *testCeremony) file(t *testing.T) []byte {
+	g1, g2 := bn254.NewG1(), bn254.NewG2()
+	one := new(bn254.Fr).One()
+	n := 1 << c.power
+	sections := map[uint32][]byte{}
+	header := new(bytes.Buffer)
+	_ = binary.Write(header, binary.LittleEndian, uint32(32))
+	q := modulus.Bytes()
+	for i := len(q) - 1; i >= 0; i-- {
+		header.WriteByte(q[i])
+	}
+	_ = binary.Write(header, binary.LittleEndian, c.power)
+	_ = binary.Write(header, binary.LittleEndian, c.power)
+	sections[SectionHeader] = header.Bytes()
+	g1Section := func(scalars []*bn254.Fr) []byte {
+		out := []byte{}
+		for _, s := range scalars {
+			out = append(out, encodeG1(g1.MulScalar(g1.New(), g1.One(), s.Big()))...)
+		}
+		return out
+	}
+	g2Section := func(scalars []*bn254.Fr) []byte {
+		out := []byte{}
+		for _, s := range scalars {
+			out = append(out, encodeG2(g2.MulScalar(g2.New(), g2.One(), s.Big()))...)
+		}
+		return out
+	}
+	sections[SectionTauG1] = g1Section(c.powers(2*n-1, one))
+	sections[SectionTauG2] = g2Section(c.powers(n, one))
+	sections[SectionAlphaTauG1] = g1Section(c.powers(n, c.alpha))
+	sections[SectionBetaTauG1] = g1Section(c.powers(n, c.beta))
+	sections[SectionBetaG2] = g2Section([]*bn254.Fr{c.beta})
+	if c.withLagrange {
+		// evaluations for domain sizes 1, 2, 4, ... follow each other
+		lagrange := func(section func([]*bn254.Fr) []byte, s *bn254.Fr) []byte {
+			out := []byte{}
+			for size := 1; size <= n; size *= 2 {
+				d, _ := fft.NewDomain(size)
+				l := poly.LagrangeBasisEvaluations(d, c.tau)
+				scalars := make([]*bn254.Fr, size)
+				for i := range l {
+					scalars[i] = new(bn254.Fr).Mul(&l[i], s)
+				}
+				out = append(out, section(scalars)...)
+			}
+			return out
+		}
+		sections[SectionLagrangeTauG1] = lagrange(g1Section, one)
+		sections[SectionLagrangeTauG2] = lagrange(g2Section, one)
+		sections[SectionLagrangeAlphaTauG1] = lagrange(g1Section, c.alpha)
+		sections[SectionLagrangeBetaTauG1] = lagrange(g1Section, c.beta)
+	}
+	if c.corruptG1 {
+		// increment least significant byte of y of the second power
+		sections[SectionTauG1][g1Size+32]++
+	}
+	if c.nonSubgroupG2 {
+		in := make([]byte, 64)
+		_, _ = rand.Read(in[1:])
+		p, err := g2.MapToPointTI(in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		copy(sections[SectionTauG2][g2Size:], encodeG2(p))
+	}
+	out := new(bytes.Buffer)
+	out.WriteString("ptau")
+	_ = binary.Write(out, binary.LittleEndian, uint32(1))
+	_ = binary.Write(out, binary.LittleEndian, uint32(len(sections)))
+	for _, typ := range []uint32{1, 2, 3, 4, 5, 6, 12, 13, 14, 15} {
+		data, ok := sections[typ]
+		if !ok {
+			continue
+		}
+		_ = binary.Write(out, binary.LittleEndian, typ)
+		_ = binary.Write(out, binary.LittleEndian, uint64(len(data)))
+		out.Write(data)
+	}
+	return out.Bytes()
+}
+
+func newTestCeremony(power uint32) *testCeremony {
+	c := &testCeremony{power: power}
+	c.tau, _ = new(bn254.Fr).Rand(rand.Reader)
+	c.alpha, _ = new(bn254.Fr).Rand(rand.Reader)
+	c.beta, _ = new(bn254.Fr).Rand(rand.Reader)
+	return c
+}
+
+func TestReader(t *testing.T) {
+	c := newTestCeremony(3)
+	r, err := NewReader(bytes.NewReader(c.file(t)), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Header.Power != 3 || r.Header.CeremonyPower != 3 {
+		t.Fatal("bad header")
+	}
+	if len(r.Sections()) != 6 || r.Sections()[0] != SectionHeader {
+		t.Fatal("bad sections")
+	}
+	g1, g2 := bn254.NewG1(), bn254.NewG2()
+	one := new(bn254.Fr).One()
+	checkG1 := func(points []*bn254.PointG1, scalars []*bn254.Fr) {
+		for i := range points {
+			if !g1.Equal(points[i], g1.MulScalar(g1.New(), g1.One(), scalars[i].Big())) {
+				t.Fatal("bad g1 point")
+			}
+		}
+	}
+	tauG1, err := r.TauG1(15)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkG1(tauG1, c.powers(15, one))
+	alphaTauG1, err := r.AlphaTauG1(8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkG1(alphaTauG1, c.powers(8, c.alpha))
+	betaTauG1, err := r.BetaTauG1(4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkG1(betaTauG1, c.powers(4, c.beta))
+	tauG2, err := r.TauG2(8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, s := range c.powers(8, one) {
+		if !g2.Equal(tauG2[i], g2.MulScalar(g2.New(), g2.One(), s.Big())) {
+			t.Fatal("bad g2 point")
+		}
+	}
+	betaG2, err := r.BetaG2()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !g2.Equal(betaG2, g2.MulScalar(g2.New(), g2.One(), c.beta.Big())) {
+		t.Fatal("bad beta")
+	}
+	if _, err := r.TauG1(16); err == nil {
+		t.Fatal("section is shorter than requested")
+	}
+	if _, err := r.TauG2(-1); err == nil {
+		t.Fatal("negative number of points should be rejected")
+	}
+	if _, err := r.LagrangeTauG1(3); err == nil {
+		t.Fatal("size should be a power of two")
+	}
+	if _, err := NewReader(bytes.NewReader([]byte("ptaa\x01\x00\x00\x00\x00\x00\x00\x00")), false); err == nil {
+		t.Fatal("bad magic should be rejected")
+	}
+}
+
+func TestReaderValidation(t *testing.T) {
+	c := newTestCeremony(2)
+	c.corruptG1 = true
+	file := c.file(t)
+	r, _ := NewReader(bytes.NewReader(file), false)
+	if _, err := r.TauG1(2); err != nil {
+		t.Fatal("points are not validated")
+	}
+	r, _ = NewReader(bytes.NewReader(file), true)
+	if _, err := r.TauG1(2); err == nil {
+		t.Fatal("point is not on curve")
+	}
+	c.corruptG1 = false
+	c.nonSubgroupG2 = true
+	r, _ = NewReader(bytes.NewReader(c.file(t)), true)
+	if _, err := r.TauG2(1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.TauG2(2); err == nil {
+		t.Fatal("point is not in G2")
+	}
+}
+
+func TestLagrange(t *testing.T) {
+	for _, withLagrange := range []bool{false, true} {
+		c := newTestCeremony(3)
+		c.withLagrange = withLagrange
+		r, err := NewReader(bytes.NewReader(c.file(t)), true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		g1, g2 := bn254.NewG1(), bn254.NewG2()
+		one := new(bn254.Fr).One()
+		for _, size := range []int{1, 2, 8} {
+			d, _ := fft.NewDomain(size)
+			l := poly.LagrangeBasisEvaluations(d, c.tau)
+			for _, tc := range []struct {
+				read func(int) ([]*bn254.PointG1, error)
+				s    *bn254.Fr
+			}{{r.LagrangeTauG1, one}, {r.LagrangeAlphaTauG1, c.alpha}, {r.LagrangeBetaTauG1, c.beta}} {
+				points, err := tc.read(size)
+				if err != nil {
+					t.Fatal(err)
+				}
+				for i := range points {
+					s := new(bn254.Fr).Mul(&l[i], tc.s)
+					if !g1.Equal(points[i], g1.MulScalar(g1.New(), g1.One(), s.Big())) {
+						t.Fatal("bad lagrange basis point")
+					}
+				}
+			}
+			points, err := r.LagrangeTauG2(size)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i := range points {
+				if !g2.Equal(points[i], g2.MulScalar(g2.New(), g2.One(), l[i].Big())) {
+					t.Fatal("bad lagrange basis point in g2")
+				}
+			}
+		}
+		if _, err := r.LagrangeTauG1(16); err == nil {
+			t.Fatal("size is larger than 2^power")
+		}
+		if _, err := r.LagrangeTauG2(3); err == nil {
+			t.Fatal("size should be a power of two")
+		}
+	}
+}
@@ -0,0 +1,487 @@
+package bn254
+
+import (
+	"errors"
+	"math/big"
+	"sync"
+)
+
+// Concurrency notes:
+//
+// PointG1, PointG2, E and PreparedG2 are plain values. They can be shared across goroutines
+// as long as no goroutine writes to them, PreparedG2 is never written after PrepareG2 returns.
+//
+// G1, G2, GT and Engine instances carry scratch space and Engine also carries its pair stack.
+// They must not be shared across goroutines. Either construct one instance per goroutine
+// or use SafeG1, SafeG2, SafeGT and SafeEngine which are backed by instance pools.
+
+// SafeG1 is a G1 group instance that is safe for concurrent use.
+// Each call borrows a G1 instance from a pool.
+type SafeG1 struct {
+	pool sync.Pool
+}
+
+// NewSafeG1 constructs a new concurrency safe G1 instance.
+func NewSafeG1() *SafeG1 {
+	g := &SafeG1{}
+	g.pool.New = func() interface{} { return NewG1() }
+	return g
+}
+
+func (g *SafeG1) get() *G1 {
+	return g.pool.Get().(*G1)
+}
+
+func (g *SafeG1) put(g1 *G1) {
+	g.pool.Put(g1)
+}
+
+// Q returns group order of BN254 in big.Int
+func (g *SafeG1) Q() *big.Int {
+	return new(big.Int).Set(q)
+}
+
+// FromBytes constructs a new point given uncompressed byte input.
+func (g *SafeG1) FromBytes(in []byte) (*PointG1, error) {
+	g1 := g.get()
+	defer g.put(g1)
+	return g1.FromBytes(in)
+}
+
+// ToBytes serializes a point into bytes in uncompressed form. Given point is not modified.
+func (g *SafeG1) ToBytes(p *PointG1) []byte {
+	g1 := g.get()
+	defer g.put(g1)
+	return g1.ToBytes(new(PointG1).Set(p))
+}
+
+// New creates a new G1 Point which is equal to zero in other words point at infinity.
+func (g *SafeG1) New() *PointG1 {
+	return new(PointG1).Zero()
+}
+
+// Zero returns a new G1 Point which is equal to point at infinity.
+func (g *SafeG1) Zero() *PointG1 {
+	return new(PointG1).Zero()
+}
+
+// One returns a new G1 Point which is equal to generator point.
+func (g *SafeG1) One() *PointG1 {
+	return new(PointG1).Set(&g1One)
+}
+
+// IsZero returns true if given point is equal to zero.
+func (g *SafeG1) IsZero(p *PointG1) bool {
+	return p[2].isZero()
+}
+
+// Equal checks if given two G1 point is equal in their affine form.
+func (g *SafeG1) Equal(p1, p2 *PointG1) bool {
+	g1 := g.get()
+	defer g.put(g1)
+	return g1.Equal(p1, p2)
+}
+
+// InCorrectSubgroup checks whether given point is in correct subgroup.
+func (g *SafeG1) InCorrectSubgroup(p *PointG1) bool {
+	g1 := g.get()
+	defer g.put(g1)
+	return g1.InCorrectSubgroup(p)
+}
+
+// IsOnCurve checks a G1 point is on curve.
+func (g *SafeG1) IsOnCurve(p *PointG1) bool {
+	g1 := g.get()
+	defer g.put(g1)
+	return g1.IsOnCurve(p)
+}
+
+// Affine calculates affine form of given G1 point.
+func (g *SafeG1) Affine(p *PointG1) *PointG1 {
+	g1 := g.get()
+	defer g.put(g1)
+	return g1.Affine(p)
+}
+
+// Add adds two G1 points p1, p2 and assigns the result to point at first argument.
+func (g *SafeG1) Add(r, p1, p2 *PointG1) *PointG1 {
+	g1 := g.get()
+	defer g.put(g1)
+	return g1.Add(r, p1, p2)
+}
+
+// Double doubles a G1 point p and assigns the result to the point at first argument.
+func (g *SafeG1) Double(r, p *PointG1) *PointG1 {
+	g1 := g.get()
+	defer g.put(g1)
+	return g1.Double(r, p)
+}
+
+// Neg negates a G1 point p and assigns the result to the point at first argument.
+func (g *SafeG1) Neg(r, p *PointG1) *PointG1 {
+	g1 := g.get()
+	defer g.put(g1)
+	return g1.Neg(r, p)
+}
+
+// Sub subtracts two G1 points p1, p2 and assigns the result to point at first argument.
+func (g *SafeG1) Sub(c, a, b *PointG1) *PointG1 {
+	g1 := g.get()
+	defer g.put(g1)
+	return g1.Sub(c, a, b)
+}
+
+// MulScalar multiplies a point by given scalar value in big.Int and assigns the result to point at first argument.
+func (g *SafeG1) MulScalar(c, p *PointG1, e *big.Int) *PointG1 {
+	g1 := g.get()
+	defer g.put(g1)
+	return g1.MulScalar(c, p, e)
+}
+
+// MultiExp calculates multi exponentiation. See G1.MultiExp.
+// Unlike G1.MultiExp given scalar slice is not modified.
+func (g *SafeG1) MultiExp(r *PointG1, points []*PointG1, powers []*big.Int) (*PointG1, error) {
+	g1 := g.get()
+	defer g.put(g1)
+	return g1.MultiExp(r, points, append([]*big.Int{}, powers...))
+}
+
+// SafeG2 is a G2 group instance that is safe for concurrent use.
+// Each call borrows a G2 instance from a pool.
+type SafeG2 struct {
+	pool sync.Pool
+}
+
+// NewSafeG2 constructs a new concurrency safe G2 instance.
+func NewSafeG2() *SafeG2 {
+	g := &SafeG2{}
+	g.pool.New = func() interface{} { return NewG2() }
+	return g
+}
+
+func (g *SafeG2) get() *G2 {
+	return g.pool.Get().(*G2)
+}
+
+func (g *SafeG2) put(g2 *G2) {
+	g.pool.Put(g2)
+}
+
+// Q returns group order of BN254 in big.Int
+func (g *SafeG2) Q() *big.Int {
+	return new(big.Int).Set(q)
+}
+
+// FromBytes constructs a new point given uncompressed byte input.
+func (g *SafeG2) FromBytes(in []byte) (*PointG2, error) {
+	g2 := g.get()
+	defer g.put(g2)
+	return g2.FromBytes(in)
+}
+
+// ToBytes serializes a point into bytes in uncompressed form. Given point is not modified.
+func (g *SafeG2) ToBytes(p *PointG2) []byte {
+	g2 := g.get()
+	defer g.put(g2)
+	return g2.ToBytes(new(PointG2).Set(p))
+}
+
+// New creates a new G2 Point which is equal to zero in other words point at infinity.
+func (g *SafeG2) New() *PointG2 {
+	return new(PointG2).Zero()
+}
+
+// Zero returns a new G2 Point which is equal to point at infinity.
+func (g *SafeG2) Zero() *PointG2 {
+	return new(PointG2).Zero()
+}
+
+// One returns a new G2 Point which is equal to generator point.
+func (g *SafeG2) One() *PointG2 {
+	return new(PointG2).Set(&g2One)
+}
+
+// IsZero returns true if given point is equal to zero.
+func (g *SafeG2) IsZero(p *PointG2) bool {
+	return p[2].isZero()
+}
+
+// Equal checks if given two G2 point is equal in their affine form.
+func (g *SafeG2) Equal(p1, p2 *PointG2) bool {
+	g2 := g.get()
+	defer g.put(g2)
+	return g2.Equal(p1, p2)
+}
+
+// InCorrectSubgroup checks whether given point is in correct subgroup.
+func (g *SafeG2) InCorrectSubgroup(p *PointG2) bool {
+	g2 := g.get()
+	defer g.put(g2)
+	return g2.InCorrectSubgroup(p)
+}
+
+// IsOnCurve checks a G2 point is on curve.
+func (g *SafeG2) IsOnCurve(p *PointG2) bool {
+	g2 := g.get()
+	defer g.put(g2)
+	return g2.IsOnCurve(p)
+}
+
+// Affine calculates affine form of given G2 point.
+func (g *SafeG2) Affine(p *PointG2) *PointG2 {
+	g2 := g.get()
+	defer g.put(g2)
+	return g2.Affine(p)
+}
+
+// Add adds two G2 points p1, p2 and assigns the result to point at first argument.
+func (g *SafeG2) Add(r, p1, p2 *PointG2) *PointG2 {
+	g2 := g.get()
+	defer g.put(g2)
+	return g2.Add(r, p1, p2)
+}
+
+// Double doubles a G2 point p and assigns the result to the point at first argument.
+func (g *SafeG2) Double(r, p *PointG2) *PointG2 {
+	g2 := g.get()
+	defer g.put(g2)
+	return g2.Double(r, p)
+}
+
+// Neg negates a G2 point p and assigns the result to the point at first argument.
+func (g *SafeG2) Neg(r, p *PointG2) *PointG2 {
+	g2 := g.get()
+	defer g.put(g2)
+	return g2.Neg(r, p)
+}
+
+// Sub subtracts two G2 points p1, p2 and assigns the result to point at first argument.
+func (g *SafeG2) Sub(c, a, b *PointG2) *PointG2 {
+	g2 := g.get()
+	defer g.put(g2)
+	return g2.Sub(c, a, b)
+}
+
+// MulScalar multiplies a point by given scalar value in big.Int and assigns the result to point at first argument.
+func (g *SafeG2) MulScalar(c, p *PointG2, e *big.Int) *PointG2 {
+	g2 := g.get()
+	defer g.put(g2)
+	return g2.MulScalar(c, p, e)
+}
+
+// MultiExp calculates multi exponentiation. See G2.MultiExp.
+// Unlike G2.MultiExp given scalar slice is not modified.
+func (g *SafeG2) MultiExp(r *PointG2, points []*PointG2, powers []*big.Int) (*PointG2, error) {
+	g2 := g.get()
+	defer g.put(g2)
+	return g2.MultiExp(r, points, append([]*big.Int{}, powers...))
+}
+
+// SafeGT is a target group instance that is safe for concurrent use.
+// Each call borrows a GT instance from a pool.
+type SafeGT struct {
+	pool sync.Pool
+}
+
+// NewSafeGT constructs a new concurrency safe target group instance.
+func NewSafeGT() *SafeGT {
+	g := &SafeGT{}
+	g.pool.New = func() interface{} { return NewGT() }
+	return g
+}
+
+func (g *SafeGT) get() *GT {
+	return g.pool.Get().(*GT)
+}
+
+func (g *SafeGT) put(gt *GT) {
+	g.pool.Put(gt)
+}
+
+// Q returns group order in big.Int.
+func (g *SafeGT) Q() *big.Int {
+	return new(big.Int).Set(q)
+}
+
+// FromBytes expects 384 byte input and returns target group element. See GT.FromBytes.
+func (g *SafeGT) FromBytes(in []byte) (*E, error) {
+	gt := g.get()
+	defer g.put(gt)
+	return gt.FromBytes(in)
+}
+
+// FromBytesUnchecked expects 384 byte input and returns target group element without subgroup check.
+func (g *SafeGT) FromBytesUnchecked(in []byte) (*E, error) {
+	gt := g.get()
+	defer g.put(gt)
+	return gt.FromBytesUnchecked(in)
+}
+
+// ToBytes serializes target group element.
+func (g *SafeGT) ToBytes(e *E) []byte {
+	gt := g.get()
+	defer g.put(gt)
+	return gt.ToBytes(e)
+}
+
+// FromCompressed expects 192 byte torus compressed input and returns target group element. See GT.FromCompressed.
+func (g *SafeGT) FromCompressed(in []byte) (*E, error) {
+	gt := g.get()
+	defer g.put(gt)
+	return gt.FromCompressed(in)
+}
+
+// ToCompressed serializes target group element into 192 bytes. See GT.ToCompressed.
+func (g *SafeGT) ToCompressed(e *E) []byte {
+	gt := g.get()
+	defer g.put(gt)
+	return gt.ToCompressed(e)
+}
+
+// InCorrectSubgroup checks whether given element is in order q subgroup of multiplicative group.
+func (g *SafeGT) InCorrectSubgroup(e *E) bool {
+	gt := g.get()
+	defer g.put(gt)
+	return gt.InCorrectSubgroup(e)
+}
+
+// New initializes a new target group element which is equal to one
+func (g *SafeGT) New() *E {
+	return new(E).One()
+}
+
+// Mul multiplies two element `a` and `b` and assigns the result to the element in first argument.
+func (g *SafeGT) Mul(c, a, b *E) {
+	gt := g.get()
+	defer g.put(gt)
+	gt.Mul(c, a, b)
+}
+
+// Square squares an element `a` and assigns the result to the element in first argument.
+func (g *SafeGT) Square(c, a *E) {
+	gt := g.get()
+	defer g.put(gt)
+	gt.Square(c, a)
+}
+
+// Inverse inverses an element `a` and assigns the result to the element in first argument.
+func (g *SafeGT) Inverse(c, a *E) {
+	gt := g.get()
+	defer g.put(gt)
+	gt.Inverse(c, a)
+}
+
+// Exp exponents an element `a` by a scalar `s` and assigns the result to the element in first argument. See GT.Exp.
+func (g *SafeGT) Exp(c, a *E, s *big.Int) {
+	gt := g.get()
+	defer g.put(gt)
+	gt.Exp(c, a, s)
+}
+
+// ExpConstantTime exponents an element by a secret scalar. See GT.ExpConstantTime.
+func (g *SafeGT) ExpConstantTime(c, a *E, s *Fr) {
+	gt := g.get()
+	defer g.put(gt)
+	gt.ExpConstantTime(c, a, s)
+}
+
+// MultiExp calculates multi exponentiation. See GT.MultiExp.
+func (g *SafeGT) MultiExp(r *E, elems []*E, scalars []*big.Int) (*E, error) {
+	gt := g.get()
+	defer g.put(gt)
+	return gt.MultiExp(r, elems, scalars)
+}
+
+// SafeEngine is a pairing engine that is safe for concurrent use.
+// Unlike Engine it has no pair stack, pairs are given to each call instead.
+type SafeEngine struct {
+	pool sync.Pool
+}
+
+// NewSafeEngine constructs a new concurrency safe pairing engine.
+func NewSafeEngine() *SafeEngine {
+	e := &SafeEngine{}
+	e.pool.New = func() interface{} { return NewEngine() }
+	return e
+}
+
+func (e *SafeEngine) get() *Engine {
+	return e.pool.Get().(*Engine)
+}
+
+func (e *SafeEngine) put(engine *Engine) {
+	engine.Reset()
+	e.pool.Put(engine)
+}
+
+func (e *SafeEngine) load(engine *Engine, g1s []*PointG1, g2s []*PointG2) error {
+	if len(g1s) != len(g2s) {
+		return errors.New("g1 and g2 point vectors should be in same length")
+	}
+	for i := 0; i < len(g1s); i++ {
+		engine.AddPair(g1s[i], g2s[i])
+	}
+	return nil
+}
+
+func (e *SafeEngine) loadPrepared(engine *Engine, g1s []*PointG1, g2s []*PreparedG2) error {
+	if len(g1s) != len(g2s) {
+		return errors.New("g1 and g2 point vectors should be in same length")
+	}
+	for i := 0; i < len(g1s); i++ {
+		engine.AddPairPrepared(g1s[i], g2s[i])
+	}
+	return nil
+}
+
+// PrepareG2 precomputes line coefficients of a G2 point. See Engine.PrepareG2.
+func (e *SafeEngine) PrepareG2(p *PointG2) *PreparedG2 {
+	engine := e.get()
+	defer e.put(engine)
+	return engine.PrepareG2(p)
+}
+
+// Check computes product of pairings of given pairs and checks if result is equal to one.
+// Length of g1 and g2 points are expected to be equal, otherwise an error is returned.
+func (e *SafeEngine) Check(g1s []*PointG1, g2s []*PointG2) (bool, error) {
+	engine := e.get()
+	defer e.put(engine)
+	if err := e.load(engine, g1s, g2s); err != nil {
+		return false, err
+	}
+	return engine.Check(), nil
+}
+
+// Result computes product of pairings of given pairs and returns target group element as result.
+// Length of g1 and g2 points are expected to be equal, otherwise an error is returned.
+func (e *SafeEngine) Result(g1s []*PointG1, g2s []*PointG2) (*E, error) {
+	engine := e.get()
+	defer e.put(engine)
+	if err := e.load(engine, g1s, g2s); err != nil {
+		return nil, err
+	}
+	return engine.Result(), nil
+}
+
+// CheckPrepared computes product of pairings of given pairs with prepared G2 points and checks if result is equal to one.
+// Prepared points can be shared across concurrent calls.
+// Length of g1 and g2 points are expected to be equal, otherwise an error is returned.
+func (e *SafeEngine) CheckPrepared(g1s []*PointG1, g2s []*PreparedG2) (bool, error) {
+	engine := e.get()
+	defer e.put(engine)
+	if err := e.loadPrepared(engine, g1s, g2s); err != nil {
+		return false, err
+	}
+	return engine.Check(), nil
+}
+
+// ResultPrepared computes product of pairings of given pairs with prepared G2 points and returns target group element as result.
+// Length of g1 and g2 points are expected to be equal, otherwise an error is returned.
+func (e *SafeEngine) ResultPrepared(g1s []*PointG1, g2s []*PreparedG2) (*E, error) {
+	engine := e.get()
+	defer e.put(engine)
+	if err := e.loadPrepared(engine, g1s, g2s); err != nil {
+		return nil, err
+	}
+	return engine.Result(), nil
+}
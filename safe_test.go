@@ -0,0 +1,186 @@
+package bn254
+
+import (
+	"math/big"
+	"sync"
+	"testing"
+)
+
+// run with -race to detect data races
+
+func TestSafeG1Concurrent(t *testing.T) {
+	g, safe := NewG1(), NewSafeG1()
+	a, b := g.rand(), g.rand()
+	s := randScalar(q)
+	expectedSum, expectedMul := g.New(), g.New()
+	g.Add(expectedSum, a, b)
+	g.MulScalar(expectedMul, a, s)
+	var wg sync.WaitGroup
+	errs := make(chan string, 16)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r := safe.New()
+			for j := 0; j < 4; j++ {
+				safe.Add(r, a, b)
+				if !safe.Equal(r, expectedSum) {
+					errs <- "bad concurrent addition"
+					return
+				}
+				safe.MulScalar(r, a, s)
+				if !safe.Equal(r, expectedMul) {
+					errs <- "bad concurrent multiplication"
+					return
+				}
+				_ = safe.ToBytes(a)
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatal(err)
+	}
+}
+
+func TestSafeG2Concurrent(t *testing.T) {
+	g, safe := NewG2(), NewSafeG2()
+	a, b := g.rand(), g.rand()
+	s := randScalar(q)
+	expectedSum, expectedMul := g.New(), g.New()
+	g.Add(expectedSum, a, b)
+	g.MulScalar(expectedMul, a, s)
+	var wg sync.WaitGroup
+	errs := make(chan string, 16)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r := safe.New()
+			for j := 0; j < 2; j++ {
+				safe.Add(r, a, b)
+				if !safe.Equal(r, expectedSum) {
+					errs <- "bad concurrent addition"
+					return
+				}
+				safe.MulScalar(r, a, s)
+				if !safe.Equal(r, expectedMul) {
+					errs <- "bad concurrent multiplication"
+					return
+				}
+				_ = safe.ToBytes(a)
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatal(err)
+	}
+}
+
+func TestSafeEngineConcurrent(t *testing.T) {
+	g1, g2 := NewG1(), NewG2()
+	e := NewSafeEngine()
+	// e(a * G1, b * G2) == e((a * b) * G1, G2)
+	a, b := big.NewInt(17), big.NewInt(117)
+	P1, P2, H1 := g1.One(), g2.One(), g1.One()
+	g1.MulScalar(P1, P1, a)
+	g2.MulScalar(P2, P2, b)
+	g1.MulScalar(H1, H1, new(big.Int).Mul(a, b))
+	g1.Neg(H1, H1)
+	prepared := e.PrepareG2(g2.One())
+	g1s, g2s := []*PointG1{P1, H1}, []*PointG2{P2, g2.One()}
+	preparedG2s := []*PreparedG2{e.PrepareG2(P2), prepared}
+	expected, err := e.Result([]*PointG1{P1}, []*PointG2{P2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var wg sync.WaitGroup
+	errs := make(chan string, 16)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ok, err := e.Check(g1s, g2s)
+			if err != nil || !ok {
+				errs <- "bad concurrent pairing check"
+				return
+			}
+			r, err := e.Result([]*PointG1{P1}, []*PointG2{P2})
+			if err != nil || !r.Equal(expected) {
+				errs <- "bad concurrent pairing result"
+				return
+			}
+			// prepared points are shared across engines
+			ok, err = e.CheckPrepared(g1s, preparedG2s)
+			if err != nil || !ok {
+				errs <- "bad concurrent pairing check with prepared points"
+				return
+			}
+			r, err = e.ResultPrepared([]*PointG1{P1}, preparedG2s[:1])
+			if err != nil || !r.Equal(expected) {
+				errs <- "bad concurrent pairing result with prepared points"
+				return
+			}
+			engine := NewEngine()
+			engine.AddPair(P1, P2)
+			engine.AddPairPrepared(H1, prepared)
+			if !engine.Check() {
+				errs <- "bad concurrent pairing check with prepared point"
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatal(err)
+	}
+	if _, err := e.Check(g1s, g2s[:1]); err == nil {
+		t.Fatal("length mismatch should be rejected")
+	}
+	if _, err := e.CheckPrepared(g1s, preparedG2s[:1]); err == nil {
+		t.Fatal("length mismatch should be rejected")
+	}
+}
+
+func TestSafeGTConcurrent(t *testing.T) {
+	e := NewEngine()
+	gt, safe := e.GT(), NewSafeGT()
+	a := e.AddPair(e.G1.rand(), e.G2.rand()).Result()
+	s := randScalar(q)
+	expectedExp, expectedSquare := new(E), new(E)
+	gt.Exp(expectedExp, a, s)
+	gt.Square(expectedSquare, a)
+	var wg sync.WaitGroup
+	errs := make(chan string, 16)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r := safe.New()
+			for j := 0; j < 2; j++ {
+				safe.Exp(r, a, s)
+				if !r.Equal(expectedExp) {
+					errs <- "bad concurrent exponentiation"
+					return
+				}
+				safe.Square(r, a)
+				if !r.Equal(expectedSquare) {
+					errs <- "bad concurrent squaring"
+					return
+				}
+				if _, err := safe.FromBytes(safe.ToBytes(a)); err != nil {
+					errs <- "bad concurrent serialization"
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatal(err)
+	}
+}